@@ -0,0 +1,67 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package alphabets
+
+import (
+	"bufio"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/sixafter/nanoid-cli/internal/alphabet"
+	"github.com/sixafter/nanoid-cli/internal/entropy"
+	"github.com/spf13/cobra"
+)
+
+// NewAlphabetsCommand creates and returns the alphabets command
+func NewAlphabetsCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "alphabets",
+		Short: "Work with built-in alphabet presets",
+		Long: `Work with the built-in alphabet presets accepted by --preset.
+
+Besides a preset name, --preset accepts an expression: a comma-separated list of
+preset names, ranges such as "a-z", and literal characters, applied from left to
+right. A term prefixed with "-" removes its characters instead, and the named set
+"lookalikes" holds characters that are easily confused, so
+"a-z,0-9,-lookalikes" is lowercase letters and digits without them. A backslash
+escapes a literal comma or hyphen.`,
+	}
+
+	cmd.AddCommand(newListCommand())
+
+	return cmd
+}
+
+// newListCommand creates and returns the alphabets list command
+func newListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the built-in alphabet presets",
+		Long:  `List every built-in alphabet preset with its size, entropy per character, and characters.`,
+		Args:  cobra.NoArgs,
+		RunE:  runList, // Use RunE to handle errors gracefully
+	}
+}
+
+// runList is the main execution function for the alphabets list command
+func runList(cmd *cobra.Command, _ []string) error {
+	presets := alphabet.Presets()
+
+	width := len("NAME")
+	for _, p := range presets {
+		width = max(width, len(p.Name))
+	}
+
+	writer := bufio.NewWriter(cmd.OutOrStdout())
+
+	_, _ = fmt.Fprintf(writer, "%-*s  %4s  %9s  %s\n", width, "NAME", "SIZE", "BITS/CHAR", "CHARACTERS")
+	for _, p := range presets {
+		size := utf8.RuneCountInString(p.Chars)
+		_, _ = fmt.Fprintf(writer, "%-*s  %4d  %9.2f  %s\n", width, p.Name, size, entropy.PerChar(size), p.Chars)
+	}
+
+	return writer.Flush()
+}
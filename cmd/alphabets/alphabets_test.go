@@ -0,0 +1,47 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package alphabets
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sixafter/nanoid-cli/internal/alphabet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlphabetsCommand_List(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewAlphabetsCommand()
+	cmd.SetArgs([]string{"list"})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	err := cmd.Execute()
+	is.NoError(err, "Expected no error on alphabets list")
+
+	lines := strings.Split(strings.TrimSpace(outBuf.String()), "\n")
+	is.Len(lines, len(alphabet.Presets())+1, "Expected a header and one line per preset")
+	is.Equal([]string{"NAME", "SIZE", "BITS/CHAR", "CHARACTERS"}, strings.Fields(lines[0]))
+	is.Equal([]string{"numeric", "10", "3.32", "0123456789"}, strings.Fields(lines[1]))
+	is.Contains(outBuf.String(), "base58              58       5.86  ")
+}
+
+func TestAlphabetsCommand_ListArgs(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewAlphabetsCommand()
+	cmd.SetArgs([]string{"list", "extra"})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&outBuf)
+
+	is.Error(cmd.Execute())
+}
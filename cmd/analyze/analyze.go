@@ -0,0 +1,355 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package analyze
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sixafter/nanoid"
+	"github.com/sixafter/nanoid-cli/cmd/config"
+	"github.com/sixafter/nanoid-cli/internal/alphabet"
+	"github.com/sixafter/nanoid-cli/internal/interrupt"
+	"github.com/sixafter/nanoid-cli/internal/randtest"
+	"github.com/sixafter/nanoid-cli/internal/source"
+	"github.com/spf13/cobra"
+)
+
+// Supported report formats.
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// defaultCount is the number of IDs generated per source when --count is not given.
+const defaultCount = 100_000
+
+// checkInterval is the number of IDs generated between checks for cancellation.
+const checkInterval = 1024
+
+// ErrAnalysisFailed is returned when at least one test fails for at least one sample.
+var ErrAnalysisFailed = errors.New("randomness analysis failed")
+
+var (
+	// idLength is the length of the generated IDs, or of the IDs read with --file.
+	idLength int
+
+	// alphabetChars is the alphabet the IDs are drawn from.
+	alphabetChars string
+
+	// preset names a built-in alphabet or an alphabet expression used instead of alphabetChars.
+	preset string
+
+	// count is the number of IDs generated per source.
+	count int
+
+	// sources lists the random sources to generate samples from.
+	sources []string
+
+	// file names a file of IDs to analyze instead of generating them. "-" reads from stdin.
+	file string
+
+	// alpha is the significance level below which a test fails.
+	alpha float64
+
+	// format selects how the report is written: text or json.
+	format string
+)
+
+// report is the analysis of one sample.
+type report struct {
+	Source       string            `json:"source"`
+	Count        int               `json:"count"`
+	Length       int               `json:"length"`
+	AlphabetSize int               `json:"alphabet_size"`
+	Alpha        float64           `json:"alpha"`
+	Results      []randtest.Result `json:"results"`
+	Passed       bool              `json:"passed"`
+}
+
+// NewAnalyzeCommand creates and returns the analyze command
+func NewAnalyzeCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "analyze",
+		Short: "Run statistical randomness tests on generated IDs",
+		Long: `Run statistical randomness tests on a large sample of IDs and report a p-value
+and a pass/fail verdict for each test.
+
+The sample is generated with --alphabet or --preset, --id-length, and --count
+from every source named by --source, so that sources can be compared side by
+side, or read from --file ("-" for stdin), one ID per line.
+
+The tests are a chi-square goodness-of-fit test of the character frequencies at
+every position and over all positions, the serial correlation between
+consecutive characters, a runs test, and a monobit-style frequency test on
+whether characters fall in the lower or upper half of the alphabet. A test fails
+when its p-value is below --alpha; the per-position chi-square tests share
+--alpha through a Bonferroni correction. The command exits with a non-zero
+status when any test fails.`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{interrupt.Annotation: "true"},
+		RunE:        runAnalyze, // Use RunE to handle errors gracefully
+	}
+
+	// Define flags for the analyze command
+	cmd.Flags().IntVarP(&idLength, "id-length", "l", nanoid.DefaultLength, "Length of the IDs to analyze")
+	cmd.Flags().StringVarP(&alphabetChars, "alphabet", "a", nanoid.DefaultAlphabet, "Alphabet the IDs are drawn from")
+	cmd.Flags().StringVar(&preset, "preset", "", "Built-in alphabet or alphabet expression the IDs are drawn from")
+	cmd.Flags().IntVarP(&count, "count", "c", defaultCount, "Number of IDs to generate per source")
+	cmd.Flags().StringSliceVarP(&sources, "source", "s", []string{source.Auto}, "Random sources to compare: "+strings.Join(source.Names, ", "))
+	cmd.Flags().StringVar(&file, "file", "", "Analyze IDs read from a file, one per line (\"-\" for stdin)")
+	cmd.Flags().Float64Var(&alpha, "alpha", randtest.DefaultAlpha, "Significance level below which a test fails")
+	cmd.Flags().StringVarP(&format, "format", "f", formatText, "Report format: text, json")
+	config.MarkFlagValues(cmd, "format", formatText, formatJSON)
+	cmd.MarkFlagsMutuallyExclusive("alphabet", "preset")
+	cmd.MarkFlagsMutuallyExclusive("file", "source")
+	cmd.MarkFlagsMutuallyExclusive("file", "count")
+
+	return cmd
+}
+
+// runAnalyze is the main execution function for the analyze command
+func runAnalyze(cmd *cobra.Command, _ []string) error {
+	if format != formatText && format != formatJSON {
+		return fmt.Errorf("--format must be one of: %s, %s", formatText, formatJSON)
+	}
+	if alpha <= 0 || alpha >= 1 {
+		return fmt.Errorf("--alpha must be between 0 and 1")
+	}
+	if idLength <= 0 {
+		return fmt.Errorf("--id-length must be a positive integer")
+	}
+
+	chars := alphabetChars
+	if preset != "" {
+		expanded, err := alphabet.Expand(preset)
+		if err != nil {
+			return fmt.Errorf("invalid --preset: %w", err)
+		}
+		chars = expanded
+	}
+	if _, err := alphabet.NewValidator(chars, idLength); err != nil {
+		return fmt.Errorf("invalid --alphabet: %w", err)
+	}
+	size := utf8.RuneCountInString(chars)
+
+	if file == "" && count < randtest.MinCount(size) {
+		return fmt.Errorf("--count must be at least %d for a %d-character alphabet", randtest.MinCount(size), size)
+	}
+
+	// From here on, failures are about the samples rather than the invocation.
+	cmd.SilenceUsage = true
+
+	var reports []report
+	if file != "" {
+		s, err := readSample(cmd, chars)
+		if err != nil {
+			return err
+		}
+		r, err := analyze(file, s)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, r)
+	} else {
+		for _, name := range sources {
+			s, resolved, err := generateSample(cmd.Context(), name, chars)
+			if err != nil {
+				return err
+			}
+			r, err := analyze(resolved, s)
+			if err != nil {
+				return err
+			}
+			reports = append(reports, r)
+		}
+	}
+
+	writer := bufio.NewWriter(cmd.OutOrStdout())
+	defer func() {
+		if err := writer.Flush(); err != nil {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error flushing writer: %v\n", err)
+		}
+	}()
+
+	if format == formatJSON {
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			return err
+		}
+	} else {
+		for i, r := range reports {
+			if i > 0 {
+				_, _ = fmt.Fprintln(writer)
+			}
+			writeText(writer, r)
+		}
+	}
+
+	var failed []string
+	for _, r := range reports {
+		if !r.Passed {
+			failed = append(failed, r.Source)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: %s", ErrAnalysisFailed, strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// analyze runs the randomness tests on s.
+func analyze(name string, s randtest.Sample) (report, error) {
+	results, err := randtest.Run(s, alpha)
+	if err != nil {
+		return report{}, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return report{
+		Source:       name,
+		Count:        len(s.Values) / s.Length,
+		Length:       s.Length,
+		AlphabetSize: s.AlphabetSize,
+		Alpha:        alpha,
+		Results:      results,
+		Passed:       randtest.Passed(results),
+	}, nil
+}
+
+// generateSample generates count IDs from the named source and returns them
+// together with the resolved source name. It stops early if ctx is done.
+func generateSample(ctx context.Context, name, chars string) (randtest.Sample, string, error) {
+	reader, resolved, err := source.New(name)
+	if err != nil {
+		return randtest.Sample{}, "", fmt.Errorf("invalid --source: %w", err)
+	}
+
+	generator, err := nanoid.NewGenerator(
+		nanoid.WithAlphabet(chars),
+		nanoid.WithLengthHint(uint16(idLength)),
+		nanoid.WithRandReader(reader),
+	)
+	if err != nil {
+		return randtest.Sample{}, "", fmt.Errorf("failed to initialize Nano ID generator: %w", err)
+	}
+
+	index := indexOf(chars)
+	s := randtest.Sample{Values: make([]byte, 0, count*idLength), Length: idLength, AlphabetSize: len(index)}
+	for i := range count {
+		if i%checkInterval == 0 && ctx.Err() != nil {
+			return randtest.Sample{}, "", context.Cause(ctx)
+		}
+		id, err := generator.NewWithLength(idLength)
+		if err != nil {
+			return randtest.Sample{}, "", fmt.Errorf("error generating Nano ID: %w", err)
+		}
+		for _, r := range string(id) {
+			s.Values = append(s.Values, index[r])
+		}
+	}
+
+	return s, resolved, nil
+}
+
+// readSample reads the IDs named by --file. Unless --id-length is given, every
+// ID must have the length of the first.
+func readSample(cmd *cobra.Command, chars string) (randtest.Sample, error) {
+	var in io.Reader = cmd.InOrStdin()
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return randtest.Sample{}, err
+		}
+		defer func() { _ = f.Close() }()
+		in = f
+	}
+
+	index := indexOf(chars)
+	s := randtest.Sample{AlphabetSize: len(index)}
+	if cmd.Flags().Changed("id-length") {
+		s.Length = idLength
+	}
+
+	var validator *alphabet.Validator
+	scanner := bufio.NewScanner(interrupt.Reader(cmd.Context(), in))
+	line := 0
+	for scanner.Scan() {
+		if err := cmd.Context().Err(); err != nil {
+			return randtest.Sample{}, context.Cause(cmd.Context())
+		}
+		line++
+		id := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(id) == "" {
+			continue
+		}
+
+		if validator == nil {
+			if s.Length == 0 {
+				s.Length = utf8.RuneCountInString(id)
+			}
+			var err error
+			if validator, err = alphabet.NewValidator(chars, s.Length); err != nil {
+				return randtest.Sample{}, err
+			}
+		}
+		if err := validator.Validate(id); err != nil {
+			return randtest.Sample{}, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		for _, r := range id {
+			s.Values = append(s.Values, index[r])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return randtest.Sample{}, err
+	}
+	if len(s.Values) == 0 {
+		return randtest.Sample{}, fmt.Errorf("%s: no IDs to analyze", file)
+	}
+
+	return s, nil
+}
+
+// indexOf maps every character of chars to its index in the alphabet.
+func indexOf(chars string) map[rune]byte {
+	index := make(map[rune]byte, len(chars))
+	for i, r := range []rune(chars) {
+		index[r] = byte(i)
+	}
+	return index
+}
+
+// writeText writes r as a table of test results followed by its verdict.
+func writeText(w io.Writer, r report) {
+	width := len("TEST")
+	for _, res := range r.Results {
+		width = max(width, len(res.Name))
+	}
+
+	_, _ = fmt.Fprintf(w, "Source: %s (%d IDs of %d characters over a %d-character alphabet)\n", r.Source, r.Count, r.Length, r.AlphabetSize)
+	_, _ = fmt.Fprintf(w, "%-*s  %10s  %8s  %9s  %s\n", width, "TEST", "STATISTIC", "P-VALUE", "THRESHOLD", "RESULT")
+	for _, res := range r.Results {
+		_, _ = fmt.Fprintf(w, "%-*s  %10.3f  %8.4f  %9.5f  %s\n", width, res.Name, res.Statistic, res.PValue, res.Threshold, verdict(res.Passed))
+	}
+	_, _ = fmt.Fprintf(w, "Verdict: %s\n", verdict(r.Passed))
+}
+
+// verdict returns PASS or FAIL.
+func verdict(passed bool) string {
+	if passed {
+		return "PASS"
+	}
+	return "FAIL"
+}
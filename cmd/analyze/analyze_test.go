@@ -0,0 +1,133 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package analyze
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sixafter/nanoid-cli/internal/source"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeCommand_Sources(t *testing.T) {
+	is := assert.New(t)
+
+	// A tiny alpha keeps a healthy source from failing by chance.
+	cmd := NewAnalyzeCommand()
+	cmd.SetArgs([]string{"--preset", "numeric", "--id-length", "6", "--count", "5000", "--alpha", "1e-9",
+		"--source", source.CryptoRand + "," + source.ChaCha20})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	err := cmd.Execute()
+	is.NoError(err, "Expected healthy sources to pass")
+
+	output := outBuf.String()
+	is.Contains(output, "Source: "+source.CryptoRand+" (5000 IDs of 6 characters over a 10-character alphabet)")
+	is.Contains(output, "Source: "+source.ChaCha20+" (5000 IDs")
+	is.Contains(output, "chi-square/position-6")
+	is.Contains(output, "serial-correlation")
+	is.Equal(2, strings.Count(output, "Verdict: PASS"))
+}
+
+func TestAnalyzeCommand_JSON(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewAnalyzeCommand()
+	cmd.SetArgs([]string{"--alphabet", "abcd", "--id-length", "3", "--count", "2000", "--alpha", "1e-9", "--format", "json"})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	is.NoError(cmd.Execute())
+
+	var reports []report
+	is.NoError(json.Unmarshal(outBuf.Bytes(), &reports))
+	is.Len(reports, 1)
+	is.Equal(2000, reports[0].Count)
+	is.Equal(4, reports[0].AlphabetSize)
+	is.Len(reports[0].Results, 3+4)
+	is.True(reports[0].Passed)
+}
+
+func TestAnalyzeCommand_File(t *testing.T) {
+	is := assert.New(t)
+
+	// IDs that cycle through the alphabet are far from random.
+	var ids strings.Builder
+	for i := range 1000 {
+		for j := range 4 {
+			ids.WriteByte(byte('0' + (i+j)%10))
+		}
+		ids.WriteByte('\n')
+	}
+	path := filepath.Join(t.TempDir(), "ids.txt")
+	is.NoError(os.WriteFile(path, []byte(ids.String()), 0o600))
+
+	cmd := NewAnalyzeCommand()
+	cmd.SetArgs([]string{"--preset", "numeric", "--file", path})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err := cmd.Execute()
+	is.ErrorIs(err, ErrAnalysisFailed)
+	is.Contains(outBuf.String(), "(1000 IDs of 4 characters over a 10-character alphabet)", "Expected the length of the first ID to be used")
+	is.Contains(outBuf.String(), "Verdict: FAIL")
+	is.NotContains(errBuf.String(), "Usage:")
+}
+
+func TestAnalyzeCommand_Stdin(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewAnalyzeCommand()
+	cmd.SetArgs([]string{"--alphabet", "ab", "--id-length", "2", "--file", "-"})
+	cmd.SetIn(strings.NewReader("ab\nba\nabc\n"))
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err := cmd.Execute()
+	is.ErrorContains(err, "line 3")
+}
+
+func TestAnalyzeCommand_InvalidFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"format", []string{"--format", "xml"}, "--format"},
+		{"alpha", []string{"--alpha", "1"}, "--alpha"},
+		{"length", []string{"--id-length", "0"}, "--id-length"},
+		{"count", []string{"--count", "10"}, "--count must be at least 320"},
+		{"source", []string{"--count", "1000", "--source", "bogus"}, "invalid --source"},
+		{"file and source", []string{"--file", "-", "--source", source.CryptoRand}, "none of the others can be"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			is := assert.New(t)
+
+			cmd := NewAnalyzeCommand()
+			cmd.SetArgs(tc.args)
+
+			var outBuf, errBuf bytes.Buffer
+			cmd.SetOut(&outBuf)
+			cmd.SetErr(&errBuf)
+
+			is.ErrorContains(cmd.Execute(), tc.want)
+		})
+	}
+}
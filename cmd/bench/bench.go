@@ -0,0 +1,315 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package bench
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/sixafter/nanoid-cli/cmd/config"
+	"github.com/sixafter/nanoid-cli/internal/alphabet"
+	"github.com/sixafter/nanoid-cli/internal/interrupt"
+	"github.com/sixafter/nanoid-cli/internal/source"
+	"github.com/spf13/cobra"
+)
+
+// Supported report formats.
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// Defaults of the parameter matrix.
+const (
+	defaultDuration  = 500 * time.Millisecond
+	defaultThreshold = 10.0
+)
+
+// ErrRegression is returned when --compare finds a case slower than the baseline
+// by more than --threshold.
+var ErrRegression = errors.New("performance regression")
+
+// defaultAlphabets covers ASCII and Unicode alphabets whose sizes are and are not
+// powers of two: 64, 58, 32 (Cyrillic), and 25 (Greek) characters.
+var defaultAlphabets = []string{"url-safe", "base58", "а-я", "α-ω"}
+
+// defaultLengths covers a short ID and the default Nano ID length.
+var defaultLengths = []int{8, 21}
+
+var (
+	// alphabets lists the presets or alphabet expressions to benchmark.
+	alphabets []string
+
+	// lengths lists the ID lengths to benchmark.
+	lengths []int
+
+	// sources lists the random sources to benchmark.
+	sources []string
+
+	// workerCounts lists the numbers of concurrent generators to benchmark.
+	workerCounts []int
+
+	// duration is how long each case runs.
+	duration time.Duration
+
+	// baselinePath names a previous JSON run to compare against.
+	baselinePath string
+
+	// threshold is the slowdown, in percent, above which a case is a regression.
+	threshold float64
+
+	// format selects how the report is written: text or json.
+	format string
+)
+
+// NewBenchCommand creates and returns the bench command
+func NewBenchCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "bench",
+		Short: "Benchmark ID generation across a matrix of settings",
+		Long: `Benchmark ID generation for every combination of alphabet, length, random
+source, and worker count, running each case for --duration.
+
+For every case the report gives the time per ID, the throughput, and the bytes
+and allocations per ID together with the number of garbage collections.
+--alphabet may be repeated and accepts a preset name or an alphabet expression;
+--length, --source, and --workers accept comma-separated lists.
+
+Save a run with --format json and pass it to --compare on a later run to flag
+every case that is more than --threshold percent slower; the command then exits
+with a non-zero status. Cases are matched by alphabet, length, source, and
+worker count, and cases missing from the baseline are not compared.`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{interrupt.Annotation: "true"},
+		RunE:        runBench, // Use RunE to handle errors gracefully
+	}
+
+	// Define flags for the bench command
+	cmd.Flags().StringArrayVarP(&alphabets, "alphabet", "a", defaultAlphabets, "Preset or alphabet expression to benchmark (repeatable)")
+	cmd.Flags().IntSliceVarP(&lengths, "length", "l", defaultLengths, "ID lengths to benchmark")
+	cmd.Flags().StringSliceVarP(&sources, "source", "s", defaultSources(), "Random sources to benchmark: "+strings.Join(source.Names, ", "))
+	cmd.Flags().IntSliceVarP(&workerCounts, "workers", "w", defaultWorkers(), "Numbers of concurrent generators to benchmark")
+	cmd.Flags().DurationVarP(&duration, "duration", "d", defaultDuration, "How long to run each case")
+	cmd.Flags().StringVar(&baselinePath, "compare", "", "Flag regressions against a run saved with --format json")
+	cmd.Flags().Float64Var(&threshold, "threshold", defaultThreshold, "Slowdown in percent above which --compare reports a regression")
+	cmd.Flags().StringVarP(&format, "format", "f", formatText, "Report format: text, json")
+	config.MarkFlagValues(cmd, "format", formatText, formatJSON)
+
+	return cmd
+}
+
+// defaultSources returns every concrete source permitted in the current FIPS 140 mode.
+func defaultSources() []string {
+	var names []string
+	for _, name := range source.Names {
+		if _, err := source.Resolve(name); err == nil && name != source.Auto {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// defaultWorkers returns a single worker and, on multi-core machines, GOMAXPROCS workers.
+func defaultWorkers() []int {
+	if n := runtime.GOMAXPROCS(0); n > 1 {
+		return []int{1, n}
+	}
+	return []int{1}
+}
+
+// runBench is the main execution function for the bench command
+func runBench(cmd *cobra.Command, _ []string) error {
+	cases, err := matrix()
+	if err != nil {
+		return err
+	}
+
+	var baseline map[string]Result
+	if baselinePath != "" {
+		run, err := loadBaseline(baselinePath)
+		if err != nil {
+			return fmt.Errorf("invalid --compare: %w", err)
+		}
+		baseline = index(run)
+	}
+
+	// From here on, failures are about the run rather than the invocation.
+	cmd.SilenceUsage = true
+
+	writer := bufio.NewWriter(cmd.OutOrStdout())
+	defer func() {
+		if err := writer.Flush(); err != nil {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error flushing writer: %v\n", err)
+		}
+	}()
+
+	// The text table is written a row at a time, so that long runs show progress.
+	var rows *table
+	if format == formatText {
+		rows = newTable(writer, cases, baseline != nil)
+		rows.header()
+		_ = writer.Flush()
+	}
+
+	run := Run{
+		GoVersion:  runtime.Version(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		Duration:   duration.String(),
+	}
+	regressions := 0
+	for _, c := range cases {
+		r, err := measure(cmd.Context(), c, duration)
+		if errors.Is(err, interrupt.ErrInterrupted) || errors.Is(err, interrupt.ErrTimeout) {
+			// The rows already written stand; an unfinished case is not reported.
+			return fmt.Errorf("benchmark stopped after %d of %d cases: %w", len(run.Results), len(cases), err)
+		}
+		if err != nil {
+			return fmt.Errorf("benchmark %s: %w", fmtKey(c.alphabet, c.length, c.source, c.workers), err)
+		}
+		if baseline != nil {
+			compare(&r, baseline, threshold)
+			if r.Regression {
+				regressions++
+			}
+		}
+		run.Results = append(run.Results, r)
+
+		if rows != nil {
+			rows.row(r)
+			_ = writer.Flush()
+		}
+	}
+
+	if format == formatJSON {
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(run); err != nil {
+			return err
+		}
+	}
+
+	if regressions > 0 {
+		return fmt.Errorf("%w: %d of %d cases are more than %g%% slower than %s", ErrRegression, regressions, len(cases), threshold, baselinePath)
+	}
+
+	return nil
+}
+
+// matrix validates the flags and returns every combination of them.
+func matrix() ([]benchCase, error) {
+	if format != formatText && format != formatJSON {
+		return nil, fmt.Errorf("--format must be one of: %s, %s", formatText, formatJSON)
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("--duration must be positive")
+	}
+	if threshold < 0 {
+		return nil, fmt.Errorf("--threshold must not be negative")
+	}
+	if len(alphabets) == 0 || len(lengths) == 0 || len(sources) == 0 || len(workerCounts) == 0 {
+		return nil, fmt.Errorf("--alphabet, --length, --source, and --workers must not be empty")
+	}
+	for _, l := range lengths {
+		if l <= 0 || l > math.MaxUint16 {
+			return nil, fmt.Errorf("--length must be between 1 and %d, got %d", math.MaxUint16, l)
+		}
+	}
+	for _, w := range workerCounts {
+		if w <= 0 {
+			return nil, fmt.Errorf("--workers must be positive, got %d", w)
+		}
+	}
+	for _, name := range sources {
+		if _, err := source.Resolve(name); err != nil {
+			return nil, fmt.Errorf("invalid --source: %w", err)
+		}
+	}
+
+	expanded := make([]string, len(alphabets))
+	for i, a := range alphabets {
+		chars, err := alphabet.Expand(a)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --alphabet %q: %w", a, err)
+		}
+		if _, err := alphabet.NewValidator(chars, 1); err != nil {
+			return nil, fmt.Errorf("invalid --alphabet %q: %w", a, err)
+		}
+		expanded[i] = chars
+	}
+
+	var cases []benchCase
+	for i, a := range alphabets {
+		for _, l := range lengths {
+			for _, s := range sources {
+				for _, w := range workerCounts {
+					cases = append(cases, benchCase{alphabet: a, chars: expanded[i], length: l, source: s, workers: w})
+				}
+			}
+		}
+	}
+
+	return cases, nil
+}
+
+// table writes benchmark results as aligned columns.
+type table struct {
+	w        io.Writer
+	width    int
+	baseline bool
+}
+
+// newTable returns a table wide enough for the alphabets of cases.
+func newTable(w io.Writer, cases []benchCase, baseline bool) *table {
+	width := len("ALPHABET")
+	for _, c := range cases {
+		width = max(width, len([]rune(c.alphabet)))
+	}
+	return &table{w: w, width: width, baseline: baseline}
+}
+
+// header writes the column headings.
+func (t *table) header() {
+	_, _ = fmt.Fprintf(t.w, "%s  %4s  %6s  %-11s  %7s  %10s  %12s  %8s  %9s  %4s",
+		pad("ALPHABET", t.width), "SIZE", "LENGTH", "SOURCE", "WORKERS", "NS/ID", "IDS/SEC", "B/ID", "ALLOCS/ID", "GCS")
+	if t.baseline {
+		_, _ = fmt.Fprintf(t.w, "  %10s  %8s", "BASELINE", "CHANGE")
+	}
+	_, _ = fmt.Fprintln(t.w)
+}
+
+// row writes one result, marking regressions.
+func (t *table) row(r Result) {
+	_, _ = fmt.Fprintf(t.w, "%s  %4d  %6d  %-11s  %7d  %10.1f  %12.0f  %8.1f  %9.2f  %4d",
+		pad(r.Alphabet, t.width), r.AlphabetSize, r.Length, r.Source, r.Workers, r.NsPerID, r.IDsPerSec, r.BytesPerID, r.AllocsPerID, r.GCs)
+	if t.baseline {
+		if r.BaselineNsPerID != nil {
+			_, _ = fmt.Fprintf(t.w, "  %10.1f  %+7.1f%%", *r.BaselineNsPerID, *r.ChangePercent)
+		} else {
+			_, _ = fmt.Fprintf(t.w, "  %10s  %8s", "-", "-")
+		}
+		if r.Regression {
+			_, _ = fmt.Fprint(t.w, "  REGRESSION")
+		}
+	}
+	_, _ = fmt.Fprintln(t.w)
+}
+
+// pad left-aligns s in a column of width characters, counting runes rather than bytes.
+func pad(s string, width int) string {
+	if n := len([]rune(s)); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
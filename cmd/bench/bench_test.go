@@ -0,0 +1,156 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sixafter/nanoid-cli/internal/source"
+	"github.com/stretchr/testify/assert"
+)
+
+// quick limits the matrix to short url-safe cases for the given worker counts.
+func quick(workers string, args ...string) []string {
+	return append([]string{"--alphabet", "url-safe", "--length", "8", "--source", source.CryptoRand, "--workers", workers, "--duration", "20ms"}, args...)
+}
+
+func TestBenchCommand_Text(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewBenchCommand()
+	cmd.SetArgs(quick("1,2", "--alphabet", "α-ω"))
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	is.NoError(cmd.Execute())
+
+	lines := strings.Split(strings.TrimSpace(outBuf.String()), "\n")
+	is.Len(lines, 1+4, "Expected a header and one row per case")
+	is.True(strings.HasPrefix(lines[0], "ALPHABET  SIZE  LENGTH"))
+	is.Contains(lines[1], "url-safe")
+	is.Contains(lines[3], "α-ω       ", "Expected Unicode alphabets to be padded by characters")
+	is.Contains(lines[3], " 25 ")
+}
+
+func TestBenchCommand_JSON(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewBenchCommand()
+	cmd.SetArgs(quick("1,2", "--format", "json"))
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	is.NoError(cmd.Execute())
+
+	var run Run
+	is.NoError(json.Unmarshal(outBuf.Bytes(), &run))
+	is.Equal("20ms", run.Duration)
+	is.Len(run.Results, 2)
+	for i, r := range run.Results {
+		is.Equal("url-safe", r.Alphabet)
+		is.Equal(64, r.AlphabetSize)
+		is.True(r.ASCII)
+		is.Equal(source.CryptoRand, r.Source)
+		is.Equal(i+1, r.Workers)
+		is.Positive(r.IDs)
+		is.Positive(r.NsPerID)
+		is.Positive(r.IDsPerSec)
+		is.Nil(r.ChangePercent)
+	}
+}
+
+func TestBenchCommand_Compare(t *testing.T) {
+	// A baseline that is impossibly fast for one case and impossibly slow for the other.
+	baseline := Run{Results: []Result{
+		{Alphabet: "url-safe", Length: 8, Source: source.CryptoRand, Workers: 1, NsPerID: 0.001},
+		{Alphabet: "url-safe", Length: 8, Source: source.CryptoRand, Workers: 2, NsPerID: 1e12},
+	}}
+	data, err := json.Marshal(baseline)
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+
+	t.Run("text", func(t *testing.T) {
+		is := assert.New(t)
+
+		cmd := NewBenchCommand()
+		cmd.SetArgs(quick("1,2", "--compare", path))
+
+		var outBuf, errBuf bytes.Buffer
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&errBuf)
+
+		err := cmd.Execute()
+		is.ErrorIs(err, ErrRegression)
+		is.ErrorContains(err, "1 of 2 cases")
+
+		lines := strings.Split(strings.TrimSpace(outBuf.String()), "\n")
+		is.Len(lines, 3)
+		is.Contains(lines[0], "BASELINE")
+		is.True(strings.HasSuffix(lines[1], "REGRESSION"))
+		is.NotContains(lines[2], "REGRESSION")
+		is.NotContains(errBuf.String(), "Usage:")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		is := assert.New(t)
+
+		cmd := NewBenchCommand()
+		cmd.SetArgs(quick("2,3", "--compare", path, "--format", "json"))
+
+		var outBuf bytes.Buffer
+		cmd.SetOut(&outBuf)
+
+		is.NoError(cmd.Execute(), "Expected a faster case to pass")
+
+		var run Run
+		is.NoError(json.Unmarshal(outBuf.Bytes(), &run))
+		is.Len(run.Results, 2)
+		is.NotNil(run.Results[0].ChangePercent)
+		is.Less(*run.Results[0].ChangePercent, 0.0)
+		is.False(run.Results[0].Regression)
+		is.Nil(run.Results[1].ChangePercent, "Expected cases missing from the baseline to be skipped")
+	})
+}
+
+func TestBenchCommand_InvalidFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"format", []string{"--format", "xml"}, "--format"},
+		{"duration", []string{"--duration", "0s"}, "--duration"},
+		{"length", []string{"--length", "0"}, "--length"},
+		{"workers", []string{"--workers", "0"}, "--workers"},
+		{"source", []string{"--source", "bogus"}, "invalid --source"},
+		{"alphabet", []string{"--alphabet", "nope"}, "invalid --alphabet"},
+		{"baseline", []string{"--compare", filepath.Join(t.TempDir(), "missing.json")}, "invalid --compare"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			is := assert.New(t)
+
+			cmd := NewBenchCommand()
+			cmd.SetArgs(tc.args)
+
+			var outBuf, errBuf bytes.Buffer
+			cmd.SetOut(&outBuf)
+			cmd.SetErr(&errBuf)
+
+			is.ErrorContains(cmd.Execute(), tc.want)
+			is.NotContains(outBuf.String(), "NS/ID", "Expected no benchmark to run")
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// fmtKey joins the parameters that identify a benchmark case.
+func fmtKey(alphabet string, length int, source string, workers int) string {
+	return strconv.Quote(alphabet) + "/" + strconv.Itoa(length) + "/" + source + "/" + strconv.Itoa(workers)
+}
+
+// loadBaseline reads a run previously written with --format json.
+func loadBaseline(path string) (Run, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Run{}, err
+	}
+
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return Run{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return run, nil
+}
+
+// index maps the results of run by the case they were measured for.
+func index(run Run) map[string]Result {
+	byKey := make(map[string]Result, len(run.Results))
+	for _, r := range run.Results {
+		byKey[r.key()] = r
+	}
+	return byKey
+}
+
+// compare annotates r with the ns/ID of its counterpart in baseline and the
+// relative change, flagging it as a regression when it is more than threshold
+// percent slower. Cases missing from the baseline are left unannotated.
+func compare(r *Result, baseline map[string]Result, threshold float64) {
+	base, ok := baseline[r.key()]
+	if !ok || base.NsPerID <= 0 {
+		return
+	}
+
+	change := (r.NsPerID - base.NsPerID) / base.NsPerID * 100
+	r.BaselineNsPerID = &base.NsPerID
+	r.ChangePercent = &change
+	r.Regression = change > threshold
+}
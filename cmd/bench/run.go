@@ -0,0 +1,162 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package bench
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sixafter/nanoid"
+	"github.com/sixafter/nanoid-cli/internal/source"
+)
+
+// checkInterval is the number of IDs a worker generates between checks of the stop flag.
+const checkInterval = 64
+
+// benchCase is one combination of the parameter matrix.
+type benchCase struct {
+	// alphabet is the preset name or expression as given on the command line.
+	alphabet string
+
+	// chars is the expanded alphabet.
+	chars string
+
+	// length is the length of each generated ID.
+	length int
+
+	// source names the random source.
+	source string
+
+	// workers is the number of concurrent generators.
+	workers int
+}
+
+// Result is the measurement of one benchmark case.
+type Result struct {
+	Alphabet     string  `json:"alphabet"`
+	AlphabetSize int     `json:"alphabet_size"`
+	ASCII        bool    `json:"ascii"`
+	Length       int     `json:"length"`
+	Source       string  `json:"source"`
+	Workers      int     `json:"workers"`
+	IDs          int64   `json:"ids"`
+	NsPerID      float64 `json:"ns_per_id"`
+	IDsPerSec    float64 `json:"ids_per_sec"`
+	BytesPerID   float64 `json:"bytes_per_id"`
+	AllocsPerID  float64 `json:"allocs_per_id"`
+	GCs          uint32  `json:"gcs"`
+
+	// The comparison with a baseline run, set only by --compare.
+	BaselineNsPerID *float64 `json:"baseline_ns_per_id,omitempty"`
+	ChangePercent   *float64 `json:"change_percent,omitempty"`
+	Regression      bool     `json:"regression,omitempty"`
+}
+
+// key identifies the case a result was measured for, so that runs can be compared.
+func (r Result) key() string {
+	return fmtKey(r.Alphabet, r.Length, r.Source, r.Workers)
+}
+
+// Run is a complete benchmark run, as written by --format json and read by --compare.
+type Run struct {
+	GoVersion  string   `json:"go_version"`
+	OS         string   `json:"os"`
+	Arch       string   `json:"arch"`
+	GOMAXPROCS int      `json:"gomaxprocs"`
+	Duration   string   `json:"duration"`
+	Results    []Result `json:"results"`
+}
+
+// measure generates IDs for c on c.workers goroutines until duration has elapsed.
+// Generators are built before the clock starts, and a garbage collection is forced
+// beforehand so that each case starts from a comparable heap. If ctx is done first,
+// the case is abandoned and the cause is returned.
+func measure(ctx context.Context, c benchCase, duration time.Duration) (Result, error) {
+	reader, resolved, err := source.New(c.source)
+	if err != nil {
+		return Result{}, err
+	}
+
+	generators := make([]nanoid.Interface, c.workers)
+	for i := range generators {
+		generators[i], err = nanoid.NewGenerator(
+			nanoid.WithAlphabet(c.chars),
+			nanoid.WithLengthHint(uint16(c.length)),
+			nanoid.WithRandReader(reader),
+		)
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	var (
+		wg       sync.WaitGroup
+		stop     atomic.Bool
+		total    atomic.Int64
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	start := time.Now()
+	timer := time.AfterFunc(duration, func() { stop.Store(true) })
+	cancelled := context.AfterFunc(ctx, func() { stop.Store(true) })
+	for _, generator := range generators {
+		wg.Go(func() {
+			var n int64
+			for !stop.Load() {
+				for range checkInterval {
+					if _, err := generator.NewWithLength(c.length); err != nil {
+						errOnce.Do(func() { firstErr = err })
+						stop.Store(true)
+						break
+					}
+					n++
+				}
+			}
+			total.Add(n)
+		})
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	timer.Stop()
+	cancelled()
+
+	runtime.ReadMemStats(&after)
+	if firstErr != nil {
+		return Result{}, firstErr
+	}
+	if ctx.Err() != nil {
+		return Result{}, context.Cause(ctx)
+	}
+
+	ids := total.Load()
+	r := Result{
+		Alphabet:     c.alphabet,
+		AlphabetSize: utf8.RuneCountInString(c.chars),
+		ASCII:        utf8.RuneCountInString(c.chars) == len(c.chars),
+		Length:       c.length,
+		Source:       resolved,
+		Workers:      c.workers,
+		IDs:          ids,
+		GCs:          after.NumGC - before.NumGC,
+	}
+	if ids > 0 {
+		r.NsPerID = float64(elapsed.Nanoseconds()) / float64(ids)
+		r.IDsPerSec = float64(ids) / elapsed.Seconds()
+		r.BytesPerID = float64(after.TotalAlloc-before.TotalAlloc) / float64(ids)
+		r.AllocsPerID = float64(after.Mallocs-before.Mallocs) / float64(ids)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package collision
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/sixafter/nanoid"
+	alphabets "github.com/sixafter/nanoid-cli/internal/alphabet"
+	"github.com/sixafter/nanoid-cli/internal/entropy"
+	"github.com/spf13/cobra"
+)
+
+// riskThreshold is the collision probability reported by the "time to risk" estimate.
+const riskThreshold = 0.01
+
+// hoursPerYear is the number of hours in a Julian year.
+const hoursPerYear = 365.25 * 24
+
+var (
+	// idLength specifies the length of the IDs being evaluated.
+	idLength int
+
+	// alphabet defines the set of characters the IDs are drawn from.
+	alphabet string
+
+	// preset names a built-in alphabet or an alphabet expression used instead of alphabet.
+	preset string
+
+	// rate is the number of IDs generated per hour.
+	rate float64
+
+	// count is the total number of IDs generated. When unset, it is derived from rate and period.
+	count float64
+
+	// period is the time span over which rate applies when count is not given.
+	period time.Duration
+
+	// target is the highest acceptable probability of at least one collision.
+	target float64
+)
+
+// NewCollisionCommand creates and returns the collision command
+func NewCollisionCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:     "collision",
+		Aliases: []string{"calc"},
+		Short:   "Estimate the collision probability of Nano IDs",
+		Long: `Estimate the probability of at least one collision among generated Nano IDs.
+
+Given an alphabet, an ID length, and either a total --count or an ID --rate
+(IDs per hour, applied over --period), the command reports the birthday-bound
+probability of at least one collision, how long it takes to reach a 1% collision
+risk, and the minimum ID length that keeps the risk below --target.
+
+If --id-length is not specified, a default length of 21 is used.
+If --alphabet is not specified, the default ASCII alphabet is used.
+--preset selects a built-in alphabet or an alphabet expression instead.`,
+		RunE: runCollision, // Use RunE to handle errors gracefully
+	}
+
+	// Define flags for the collision command
+	cmd.Flags().IntVarP(&idLength, "id-length", "l", nanoid.DefaultLength, "Length of the Nano IDs")
+	cmd.Flags().StringVarP(&alphabet, "alphabet", "a", nanoid.DefaultAlphabet, "Alphabet the Nano IDs are drawn from")
+	cmd.Flags().StringVar(&preset, "preset", "", "Built-in alphabet or alphabet expression the Nano IDs are drawn from")
+	cmd.Flags().Float64VarP(&rate, "rate", "r", 0, "Number of IDs generated per hour")
+	cmd.Flags().Float64VarP(&count, "count", "c", 0, "Total number of IDs generated (overrides --rate and --period)")
+	cmd.Flags().DurationVarP(&period, "period", "p", time.Duration(hoursPerYear)*time.Hour, "Time span over which --rate applies")
+	cmd.Flags().Float64VarP(&target, "target", "t", riskThreshold, "Highest acceptable collision probability for the minimum length estimate")
+	cmd.MarkFlagsMutuallyExclusive("alphabet", "preset")
+
+	return cmd
+}
+
+// runCollision is the main execution function for the collision command
+func runCollision(cmd *cobra.Command, _ []string) error {
+	if idLength <= 0 || idLength > entropy.MaxLength {
+		return fmt.Errorf("--id-length must be between 1 and %d", entropy.MaxLength)
+	}
+	if rate < 0 || count < 0 {
+		return fmt.Errorf("--rate and --count must not be negative")
+	}
+	if rate == 0 && count == 0 {
+		return fmt.Errorf("one of --rate or --count is required")
+	}
+	if period <= 0 {
+		return fmt.Errorf("--period must be positive")
+	}
+	if target <= 0 || target >= 1 {
+		return fmt.Errorf("--target must be between 0 and 1 (exclusive)")
+	}
+
+	chars := alphabet
+	if preset != "" {
+		expanded, err := alphabets.Expand(preset)
+		if err != nil {
+			return fmt.Errorf("invalid --preset: %w", err)
+		}
+		chars = expanded
+	}
+
+	// Validate the alphabet exactly as the generator would, and use its view of the
+	// alphabet size so multibyte characters are counted as single characters.
+	generator, err := nanoid.NewGenerator(
+		nanoid.WithAlphabet(chars),
+		nanoid.WithLengthHint(uint16(idLength)),
+	)
+	if err != nil {
+		return fmt.Errorf("invalid alphabet: %w", err)
+	}
+	size := int(generator.Config().AlphabetLen())
+
+	total := count
+	if total == 0 {
+		total = rate * period.Hours()
+	}
+
+	writer := bufio.NewWriter(cmd.OutOrStdout())
+	defer func() {
+		if err := writer.Flush(); err != nil {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error flushing writer: %v\n", err)
+		}
+	}()
+
+	_, _ = fmt.Fprintf(writer, "Alphabet size...........: %d characters\n", size)
+	_, _ = fmt.Fprintf(writer, "Entropy per character...: %.2f bits\n", entropy.PerChar(size))
+	_, _ = fmt.Fprintf(writer, "Entropy per ID..........: %.2f bits\n", entropy.Bits(size, idLength))
+	_, _ = fmt.Fprintf(writer, "Total IDs...............: %s\n", formatCount(total))
+	_, _ = fmt.Fprintf(writer, "Collision probability...: %.3g\n", entropy.CollisionProbability(size, idLength, total))
+	_, _ = fmt.Fprintf(writer, "IDs until 1%% risk.......: %s\n", formatCount(entropy.CountForProbability(size, idLength, riskThreshold)))
+
+	if rate > 0 {
+		hours := entropy.CountForProbability(size, idLength, riskThreshold) / rate
+		_, _ = fmt.Fprintf(writer, "Time until 1%% risk......: %s\n", formatHours(hours))
+	}
+
+	if length, ok := entropy.MinLength(size, total, target); ok {
+		_, _ = fmt.Fprintf(writer, "Minimum safe length.....: %d characters (p <= %.3g)\n", length, target)
+	} else {
+		_, _ = fmt.Fprintf(writer, "Minimum safe length.....: none up to %d characters (p <= %.3g)\n", entropy.MaxLength, target)
+	}
+
+	return nil
+}
+
+// formatCount renders an ID count, switching to scientific notation for large values.
+func formatCount(n float64) string {
+	switch {
+	case math.IsInf(n, 1):
+		return "effectively unlimited"
+	case n < 1e9:
+		return fmt.Sprintf("%.0f", n)
+	default:
+		return fmt.Sprintf("%.3g", n)
+	}
+}
+
+// formatHours renders a number of hours as a duration, or in years once it no longer
+// fits a time.Duration.
+func formatHours(hours float64) string {
+	switch {
+	case math.IsInf(hours, 1):
+		return "effectively never"
+	case hours < hoursPerYear:
+		return time.Duration(hours * float64(time.Hour)).Round(time.Second).String()
+	default:
+		return fmt.Sprintf("%.3g years", hours/hoursPerYear)
+	}
+}
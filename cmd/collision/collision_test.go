@@ -0,0 +1,99 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package collision
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollisionCommand_Rate(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewCollisionCommand()
+	cmd.SetArgs([]string{"--rate", "1000000", "--id-length", "8"})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	err := cmd.Execute()
+	is.NoError(err, "Expected no error on collision command with a rate")
+
+	output := outBuf.String()
+	is.Contains(output, "Alphabet size...........: 64 characters")
+	is.Contains(output, "Entropy per ID..........: 48.00 bits")
+	is.Contains(output, "Time until 1% risk......: 2h22m")
+	is.Contains(output, "Minimum safe length.....: 12 characters")
+}
+
+func TestCollisionCommand_UnicodeAlphabet(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewCollisionCommand()
+	cmd.SetArgs([]string{"--alphabet", "αβγδε", "--id-length", "12", "--count", "1e6"})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	err := cmd.Execute()
+	is.NoError(err, "Expected no error on collision command with a Unicode alphabet")
+
+	// Multibyte characters count once towards the alphabet size
+	output := outBuf.String()
+	is.Contains(output, "Alphabet size...........: 5 characters")
+	is.NotContains(output, "Time until 1% risk", "Expected no time estimate without a rate")
+}
+
+func TestCollisionCommand_Preset(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewCollisionCommand()
+	cmd.SetArgs([]string{"--preset", "hex-lower", "--id-length", "8", "--count", "1000"})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	err := cmd.Execute()
+	is.NoError(err, "Expected no error on collision command with a preset")
+
+	output := outBuf.String()
+	is.Contains(output, "Alphabet size...........: 16 characters")
+	is.Contains(output, "Entropy per ID..........: 32.00 bits")
+}
+
+func TestCollisionCommand_Errors(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"missing volume":      {args: []string{}, want: "one of --rate or --count is required"},
+		"invalid target":      {args: []string{"--count", "10", "--target", "1"}, want: "--target must be between 0 and 1"},
+		"duplicate alphabet":  {args: []string{"--count", "10", "--alphabet", "aa"}, want: "duplicate characters in alphabet"},
+		"invalid length":      {args: []string{"--count", "10", "--id-length", "0"}, want: "--id-length must be between 1"},
+		"invalid preset":      {args: []string{"--count", "10", "--preset", "z-a"}, want: "invalid --preset"},
+		"alphabet and preset": {args: []string{"--count", "10", "--alphabet", "abc", "--preset", "numeric"}, want: "[alphabet preset] were all set"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := assert.New(t)
+
+			cmd := NewCollisionCommand()
+			cmd.SetArgs(tc.args)
+
+			var outBuf bytes.Buffer
+			cmd.SetOut(&outBuf)
+			cmd.SetErr(&outBuf)
+
+			err := cmd.Execute()
+			is.Error(err)
+			is.True(strings.Contains(err.Error(), tc.want), "Expected %q in %q", tc.want, err.Error())
+		})
+	}
+}
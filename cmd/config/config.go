@@ -0,0 +1,266 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sixafter/nanoid-cli/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// FlagName is the persistent root flag naming the configuration file.
+const FlagName = "config"
+
+// Supported output formats.
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// unconfigured lists the commands that run without loading the configuration, so
+// that a malformed file or environment variable cannot stop them: config reports
+// such problems itself, and help, completion, and version have nothing to configure.
+var unconfigured = []string{"config", "help", "completion", "version"}
+
+// commandSettings is the effective configuration of one command.
+type commandSettings struct {
+	Command  string           `json:"command"`
+	Settings []config.Setting `json:"settings"`
+}
+
+// report is the JSON form of the effective configuration.
+type report struct {
+	File     string            `json:"file,omitempty"`
+	Commands []commandSettings `json:"commands"`
+}
+
+// NewConfigCommand creates and returns the config command
+func NewConfigCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration file and environment",
+		Long: `Inspect the settings applied from the configuration file and environment.
+
+Every flag can be set in a YAML configuration file, read from --config, from the
+file named by NANOID_CONFIG, or from $XDG_CONFIG_HOME/nanoid/config.yaml. A
+top-level key sets the flag of that name for every command that has it, and a
+section named after a command sets its flags only. A top-level value must be one
+that every command with the flag accepts, so a format such as ndjson, which only
+generate accepts, belongs in the generate section:
+
+  id-length: 12
+  generate:
+    count: 5
+    format: ndjson
+
+An environment variable such as NANOID_ID_LENGTH, or NANOID_GENERATE_COUNT for
+a single command, takes precedence over the file, and a flag given on the
+command line takes precedence over both. Persistent flags such as --timeout can
+be set the same way. The config, help, completion, and version commands run
+without applying the configuration, so "nanoid config show" can report a
+malformed file or environment variable that stops other commands.`,
+	}
+
+	cmd.AddCommand(newShowCommand())
+
+	return cmd
+}
+
+// newShowCommand creates and returns the config show command
+func newShowCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "show [command...]",
+		Short: "Show the effective configuration",
+		Long:  `Show the effective value of every flag of each command, or of the given commands, and whether it comes from the environment, the configuration file, or the default.`,
+	}
+
+	// format selects how the configuration is written: text or json.
+	var format string
+	cmd.RunE = func(cmd *cobra.Command, args []string) error { // Use RunE to handle errors gracefully
+		return runShow(cmd, args, format)
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", formatText, "Output format: text or json")
+	MarkFlagValues(cmd, "format", formatText, formatJSON)
+
+	return cmd
+}
+
+// runShow is the main execution function for the config show command
+func runShow(cmd *cobra.Command, args []string, format string) error {
+	if format != formatText && format != formatJSON {
+		return fmt.Errorf("--format must be text or json")
+	}
+
+	sets := flagSets(cmd.Root())
+	names := args
+	if len(names) == 0 {
+		for name := range sets {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+	}
+	for _, name := range names {
+		if _, ok := sets[name]; !ok {
+			return fmt.Errorf("unknown command %q", name)
+		}
+	}
+
+	cmd.SilenceUsage = true
+
+	cfg, err := Load(cmd)
+	if err != nil {
+		return err
+	}
+
+	r := report{File: cfg.Path, Commands: []commandSettings{}}
+	for _, name := range names {
+		settings, err := cfg.Resolve(name, sets[name], os.LookupEnv)
+		if err != nil {
+			return err
+		}
+		if len(settings) > 0 {
+			r.Commands = append(r.Commands, commandSettings{Command: name, Settings: settings})
+		}
+	}
+
+	writer := bufio.NewWriter(cmd.OutOrStdout())
+	defer func() {
+		if err := writer.Flush(); err != nil {
+			_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "Error flushing writer:", err)
+		}
+	}()
+
+	if format == formatJSON {
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	}
+
+	return writeText(writer, r)
+}
+
+// writeText writes the effective configuration as a table.
+func writeText(w *bufio.Writer, r report) error {
+	file := r.File
+	if file == "" {
+		file = "none"
+	}
+	if _, err := fmt.Fprintf(w, "Configuration file: %s\n\n", file); err != nil {
+		return err
+	}
+
+	commandWidth, keyWidth, valueWidth := len("COMMAND"), len("KEY"), len("VALUE")
+	for _, c := range r.Commands {
+		commandWidth = max(commandWidth, len(c.Command))
+		for _, s := range c.Settings {
+			keyWidth = max(keyWidth, len(s.Key))
+			valueWidth = max(valueWidth, utf8.RuneCountInString(s.Value))
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "%-*s  %-*s  %-*s  %s\n", commandWidth, "COMMAND", keyWidth, "KEY", valueWidth, "VALUE", "SOURCE"); err != nil {
+		return err
+	}
+	for _, c := range r.Commands {
+		for _, s := range c.Settings {
+			source := s.Origin
+			if s.Detail != "" {
+				source += " (" + s.Detail + ")"
+			}
+			if _, err := fmt.Fprintf(w, "%-*s  %-*s  %-*s  %s\n", commandWidth, c.Command, keyWidth, s.Key, valueWidth, s.Value, source); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MarkFlagValues records the only values the named flag of cmd accepts, so that a
+// configuration file setting any other value is rejected before a command runs.
+func MarkFlagValues(cmd *cobra.Command, name string, values ...string) {
+	_ = cmd.Flags().SetAnnotation(name, config.ValuesAnnotation, values)
+}
+
+// Apply sets the flags of the command being executed that were not given on the
+// command line from the environment and the configuration file. It is the
+// PersistentPreRunE of the root command.
+func Apply(cmd *cobra.Command, _ []string) error {
+	name := commandName(cmd)
+	if name == "" || slices.Contains(unconfigured, name) || strings.HasPrefix(name, "__") {
+		return nil
+	}
+
+	cfg, err := Load(cmd)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+	if _, err := cfg.Apply(name, cmd.Flags(), os.LookupEnv); err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+	return nil
+}
+
+// Load reads the configuration file named by --config, or the default one, and
+// checks it against the flags of every command of cmd's root.
+func Load(cmd *cobra.Command) (*config.Config, error) {
+	path, _ := cmd.Flags().GetString(FlagName)
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Check(flagSets(cmd.Root())); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// commandName returns the name of the top-level command that cmd belongs to,
+// which names its section of the configuration file.
+func commandName(cmd *cobra.Command) string {
+	if !cmd.HasParent() {
+		return ""
+	}
+	for cmd.Parent().HasParent() {
+		cmd = cmd.Parent()
+	}
+	return cmd.Name()
+}
+
+// flagSets returns the flags of every top-level command of root, including those
+// of its subcommands, keyed by command name.
+func flagSets(root *cobra.Command) map[string]*pflag.FlagSet {
+	sets := make(map[string]*pflag.FlagSet)
+	for _, c := range root.Commands() {
+		if !c.IsAvailableCommand() || strings.HasPrefix(c.Name(), "__") {
+			continue
+		}
+		fs := pflag.NewFlagSet(c.Name(), pflag.ContinueOnError)
+		addFlags(fs, c)
+		sets[c.Name()] = fs
+	}
+	return sets
+}
+
+// addFlags adds the flags of c and its subcommands to fs, including the persistent
+// flags they inherit, which Apply sets as well.
+func addFlags(fs *pflag.FlagSet, c *cobra.Command) {
+	fs.AddFlagSet(c.LocalFlags())
+	fs.AddFlagSet(c.InheritedFlags())
+	for _, sub := range c.Commands() {
+		addFlags(fs, sub)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sixafter/nanoid-cli/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRoot returns a root command wired like the CLI's, with a generate command
+// that records its --count, and the config command.
+func newRoot(count *int) *cobra.Command {
+	root := &cobra.Command{Use: "nanoid", PersistentPreRunE: Apply}
+	root.PersistentFlags().String(FlagName, "", "")
+	root.PersistentFlags().Duration("timeout", 0, "")
+
+	generate := &cobra.Command{Use: "generate", RunE: func(*cobra.Command, []string) error { return nil }}
+	generate.Flags().IntVarP(count, "count", "c", 1, "")
+	generate.Flags().Int("id-length", 21, "")
+
+	version := &cobra.Command{Use: "version", RunE: func(*cobra.Command, []string) error { return nil }}
+
+	root.AddCommand(generate, version, NewConfigCommand())
+	return root
+}
+
+// writeConfig writes a configuration file and returns its path.
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	return path
+}
+
+func TestApply(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(config.EnvConfig, "")
+	is := assert.New(t)
+
+	path := writeConfig(t, "count: 2\ngenerate:\n  count: 3\n")
+
+	var count int
+	root := newRoot(&count)
+	root.SetArgs([]string{"generate", "--config", path})
+	is.NoError(root.Execute())
+	is.Equal(3, count)
+
+	t.Setenv("NANOID_COUNT", "4")
+	root = newRoot(&count)
+	root.SetArgs([]string{"generate", "--config", path})
+	is.NoError(root.Execute())
+	is.Equal(4, count, "The environment wins over the file")
+
+	root = newRoot(&count)
+	root.SetArgs([]string{"generate", "--config", path, "--count", "5"})
+	is.NoError(root.Execute())
+	is.Equal(5, count, "A flag wins over the environment")
+}
+
+func TestApply_Inherited(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(config.EnvConfig, "")
+	is := assert.New(t)
+
+	// A persistent flag of the root can be set from the file as well as the environment
+	var count int
+	root := newRoot(&count)
+	root.SetArgs([]string{"generate", "--config", writeConfig(t, "timeout: 5s\ngenerate:\n  timeout: 7s\n")})
+	is.NoError(root.Execute())
+
+	generate, _, err := root.Find([]string{"generate"})
+	is.NoError(err)
+	timeout, err := generate.Flags().GetDuration("timeout")
+	is.NoError(err)
+	is.Equal("7s", timeout.String())
+}
+
+func TestApply_Unconfigured(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(config.EnvConfig, "")
+	t.Setenv("NANOID_COUNT", "many")
+	is := assert.New(t)
+
+	path := writeConfig(t, "generate:\n  count: 3\n")
+
+	// A bad environment variable stops the commands it configures...
+	var count int
+	root := newRoot(&count)
+	root.SetArgs([]string{"generate", "--config", path})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	is.Error(root.Execute())
+
+	// ...but not version, or config show, which reports it
+	root = newRoot(&count)
+	root.SetArgs([]string{"version", "--config", path})
+	is.NoError(root.Execute())
+
+	root = newRoot(&count)
+	root.SetArgs([]string{"config", "show", "generate", "--config", path})
+	var outBuf bytes.Buffer
+	root.SetOut(&outBuf)
+	is.NoError(root.Execute())
+	is.Regexp(`generate\s+count\s+many\s+env \(NANOID_COUNT\)`, outBuf.String())
+
+	// A malformed file is reported by config show rather than by the root command
+	root = newRoot(&count)
+	root.SetArgs([]string{"version", "--config", writeConfig(t, "bogus: 1\n")})
+	is.NoError(root.Execute())
+}
+
+func TestApply_Values(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(config.EnvConfig, "")
+	is := assert.New(t)
+
+	// withFormat returns a root whose generate command accepts more formats than config show
+	var count int
+	withFormat := func() *cobra.Command {
+		root := newRoot(&count)
+		generate, _, err := root.Find([]string{"generate"})
+		is.NoError(err)
+		generate.Flags().String("format", "text", "")
+		MarkFlagValues(generate, "format", "text", "json", "ndjson")
+		return root
+	}
+
+	// config show only accepts text and json, so ndjson cannot be set for every command
+	root := withFormat()
+	root.SetArgs([]string{"generate", "--config", writeConfig(t, "format: ndjson\n")})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	err := root.Execute()
+	is.ErrorIs(err, config.ErrInvalidConfig)
+	is.ErrorContains(err, `command "config": format "ndjson" must be one of: text, json; set it in a command section instead`)
+
+	root = withFormat()
+	root.SetArgs([]string{"generate", "--config", writeConfig(t, "generate:\n  format: ndjson\n")})
+	is.NoError(root.Execute())
+	generate, _, err := root.Find([]string{"generate"})
+	is.NoError(err)
+	is.Equal("ndjson", generate.Flags().Lookup("format").Value.String())
+}
+
+func TestApply_Invalid(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(config.EnvConfig, "")
+	is := assert.New(t)
+
+	var count int
+	root := newRoot(&count)
+	root.SetArgs([]string{"generate", "--config", writeConfig(t, "bogus: 1\n")})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	is.ErrorIs(root.Execute(), config.ErrInvalidConfig)
+
+	root = newRoot(&count)
+	root.SetArgs([]string{"generate", "--config", filepath.Join(t.TempDir(), "missing.yaml")})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	is.ErrorIs(root.Execute(), os.ErrNotExist)
+}
+
+func TestShowCommand(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(config.EnvConfig, "")
+	t.Setenv("NANOID_ID_LENGTH", "10")
+	is := assert.New(t)
+
+	path := writeConfig(t, "generate:\n  count: 3\n")
+
+	var count int
+	root := newRoot(&count)
+	root.SetArgs([]string{"config", "show", "generate", "--config", path})
+
+	var outBuf bytes.Buffer
+	root.SetOut(&outBuf)
+	is.NoError(root.Execute())
+
+	output := outBuf.String()
+	is.Contains(output, "Configuration file: "+path)
+	is.Regexp(`generate\s+count\s+3\s+file \(`+path+`: generate.count\)`, output)
+	is.Regexp(`generate\s+id-length\s+10\s+env \(NANOID_ID_LENGTH\)`, output)
+	is.NotContains(output, "format", "Only the requested command is shown")
+	is.Equal(1, count, "Showing the configuration must not change any flag")
+}
+
+func TestShowCommand_JSON(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(config.EnvConfig, "")
+	is := assert.New(t)
+
+	var count int
+	root := newRoot(&count)
+	root.SetArgs([]string{"config", "show", "--format", "json"})
+
+	var outBuf bytes.Buffer
+	root.SetOut(&outBuf)
+	is.NoError(root.Execute())
+
+	var r report
+	is.NoError(json.Unmarshal(outBuf.Bytes(), &r))
+	is.Empty(r.File)
+
+	var names []string
+	for _, c := range r.Commands {
+		names = append(names, c.Command)
+		if c.Command == "generate" {
+			is.Equal(config.Setting{Key: "count", Value: "1", Origin: config.OriginDefault}, c.Settings[0])
+		}
+	}
+	is.Contains(names, "config")
+	is.Contains(names, "generate")
+}
+
+func TestShowCommand_Independent(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(config.EnvConfig, "")
+	is := assert.New(t)
+
+	// Both roots exist before either runs, so a shared --format would leak from one to the other
+	var count int
+	first, second := newRoot(&count), newRoot(&count)
+
+	first.SetArgs([]string{"config", "show", "--format", "json"})
+	first.SetOut(&bytes.Buffer{})
+	is.NoError(first.Execute())
+
+	second.SetArgs([]string{"config", "show", "generate"})
+	var outBuf bytes.Buffer
+	second.SetOut(&outBuf)
+	is.NoError(second.Execute())
+	is.Contains(outBuf.String(), "Configuration file: none", "Expected text output from a command without --format json")
+}
+
+func TestShowCommand_InvalidArgs(t *testing.T) {
+	is := assert.New(t)
+
+	var count int
+	root := newRoot(&count)
+	root.SetArgs([]string{"config", "show", "nope"})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	is.ErrorContains(root.Execute(), `unknown command "nope"`)
+}
@@ -7,37 +7,70 @@ package generate
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"crypto/fips140"
+	"errors"
 	"fmt"
-	"math"
+	"io"
+	"os"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/sixafter/nanoid"
+	"github.com/sixafter/nanoid-cli/cmd/config"
+	"github.com/sixafter/nanoid-cli/internal/checksum"
+	"github.com/sixafter/nanoid-cli/internal/interrupt"
+	"github.com/sixafter/nanoid-cli/internal/registry"
+	"github.com/sixafter/nanoid-cli/internal/source"
+	"github.com/sixafter/nanoid-cli/pkg/generate"
 	"github.com/spf13/cobra"
 )
 
-var (
-	// idLength specifies the desired length of the generated ID.
-	// It determines how many characters the resulting identifier will contain.
-	idLength int
+// options holds the flags of one generate command, so that commands created by
+// separate calls to NewGenerateCommand never share state.
+type options struct {
+	generate.Options
 
-	// alphabet defines the set of characters that can be used when generating IDs.
-	// It should contain a unique sequence of runes from which random characters are selected.
-	alphabet string
+	// idType names a type in the registry whose prefix, alphabet, and length are used.
+	idType string
 
-	// count indicates how many IDs to generate during execution.
-	// Useful for batch operations or performance benchmarking.
-	count int
+	// registryPath is the YAML file that defines the types accepted by --type.
+	registryPath string
+
+	// profile names a profile from the configuration file; the root command
+	// applies its settings before generate runs.
+	profile string
 
 	// verbose controls whether detailed diagnostic or progress information is printed.
 	// When true, additional output such as timing or debug details may be displayed.
 	verbose bool
-)
+
+	// progress shows a progress line on stderr while IDs are generated, if stderr is a terminal.
+	progress bool
+
+	// statsFile receives the verbose stats block instead of stderr.
+	statsFile string
+
+	// stream generates IDs until interrupted, equivalent to --count 0.
+	stream bool
+
+	// rate limits output to a number of IDs per second, minute, or hour, such as "1000/s".
+	rate string
+
+	// splitSize starts a new output file once it reaches this size, such as "1GiB".
+	splitSize string
+}
+
+// statsLabelWidth is the width, including dot padding, of the labels in the verbose stats block.
+const statsLabelWidth = 24
 
 // NewGenerateCommand creates and returns the generate command
 func NewGenerateCommand() *cobra.Command {
+	o := &options{}
+
 	var cmd = &cobra.Command{
 		Use:   "generate",
 		Short: "Generate one or more Nano IDs",
@@ -45,104 +78,312 @@ func NewGenerateCommand() *cobra.Command {
 
 If --id-length is not specified, a default length of 21 is used.
 If --alphabet is not specified, the default ASCII alphabet is used.
-If --count is not specified, one Nano ID is generated.`,
-		RunE: runGenerate, // Use RunE to handle errors gracefully
+--preset selects a built-in alphabet (see "nanoid alphabets list") or an
+expression such as "a-z,0-9,-lookalikes" instead of --alphabet.
+--prefix is written before every ID. --type takes the prefix, separator, alphabet,
+and length of a type defined in the YAML file named by --registry.
+--profile applies the alphabet, length, prefix, source, and format of a profile
+defined in the configuration file (see "nanoid profile list"); flags given on the
+command line override it.
+--sortable starts every ID with a millisecond timestamp and sequence number written
+in the alphabet's code point order, so IDs sort in the order they were issued;
+--id-length then sets the length of the random part. "nanoid inspect" decodes it.
+--checksum appends check characters from the same alphabet to every ID body:
+"luhn" adds one and needs an alphabet with an even number of characters, while
+"weighted" adds two and detects every single-character substitution and adjacent
+transposition. Verify them with "nanoid validate --checksum".
+If --count is not specified, one Nano ID is generated.
+If --count is 0 or --stream is given, IDs are generated until the process is
+interrupted, --timeout elapses, or its output is closed; output is flushed every
+--flush-interval. A stream or batch interrupted by SIGINT or SIGTERM, or cut
+short by --timeout, stops after a whole ID and completes the document written so
+far, then exits with status 130 or 124. A batch's unfinished --output file is
+discarded, while a stream's is kept. A stream whose output is closed exits with
+status 0.
+If --rate is given, output is limited to that many IDs per second (or /m, /h),
+allowing bursts of up to --burst IDs.
+If --unique is given, duplicate IDs are regenerated so that the batch contains
+none. Up to --unique-memory IDs are tracked exactly in memory; beyond that a Bloom
+filter is used, or sorted runs are spilled to --unique-spill-dir if it is set.
+A --count that would leave too few unused IDs to find the last ones quickly is
+rejected before any are generated.
+If --workers is not specified, generation is spread across GOMAXPROCS goroutines.
+If --format is not specified, one bare ID is written per line.
+--output writes to a file instead of stdout; --split-lines or --split-size rolls
+the output across numbered files such as ids-00001.txt, each a complete document
+in the chosen format. Every file is written under a temporary name and renamed
+into place once complete, so partial files are never visible. --manifest adds a
+sidecar such as ids.manifest.json listing each file's IDs, lines, size, and
+SHA-256. Existing files are never replaced unless --force is given, in which case
+numbered files beyond those a completed run wrote, left by an earlier run, are
+removed.
+--compress compresses the output, or every --output file, with gzip or zlib on a
+separate goroutine so that compression does not slow generation; --split-size and
+the manifest's line counts then refer to the uncompressed output.
+--seed replaces the random source with a ChaCha20 stream keyed by the seed (64 hex
+digits, or any passphrase), so the same seed and flags always print the same IDs.
+Seeded IDs are predictable and NOT secure: use them only for tests and fixtures.
+Seeded generation runs on a single worker and is refused in FIPS 140 mode.
+If --source is not specified, the AES-CTR DRBG is used in FIPS 140 mode and the
+ChaCha20 PRNG otherwise. The --drbg-* flags tune the AES-CTR-DRBG and are only
+accepted when it is the active source.
+--progress redraws a line on stderr with the rate, bytes written, and, for a
+batch, the percentage done and ETA; it is ignored when stderr is not a terminal.
+--verbose prints a stats block on stderr, or --stats-file writes it to a file,
+so stdout only ever holds the generated IDs.`,
+		Annotations: map[string]string{interrupt.Annotation: "true"},
+		RunE:        o.run, // Use RunE to handle errors gracefully
 	}
 
 	// Define flags for the generate command
-	cmd.Flags().IntVarP(&idLength, "id-length", "l", nanoid.DefaultLength, "Length of the Nano ID to generate")
-	cmd.Flags().StringVarP(&alphabet, "alphabet", "a", nanoid.DefaultAlphabet, "Custom alphabet to use for Nano ID generation")
-	cmd.Flags().IntVarP(&count, "count", "c", 1, "Number of Nano IDs to generate")
-	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
+	cmd.Flags().IntVarP(&o.Length, "id-length", "l", nanoid.DefaultLength, "Length of the Nano ID to generate")
+	cmd.Flags().StringVarP(&o.Alphabet, "alphabet", "a", nanoid.DefaultAlphabet, "Custom alphabet to use for Nano ID generation")
+	cmd.Flags().StringVar(&o.Preset, "preset", "", "Built-in alphabet or alphabet expression, e.g. base58 or a-z,0-9,-lookalikes")
+	cmd.Flags().StringVar(&o.Prefix, "prefix", "", "Text written before every ID, such as usr_")
+	cmd.Flags().BoolVar(&o.Sortable, "sortable", false, "Start every ID with a timestamp so that IDs sort in the order they were issued")
+	cmd.Flags().StringVar(&o.Checksum, "checksum", "", "Append check characters using a scheme: "+strings.Join(checksum.Names, ", "))
+	cmd.Flags().StringVar(&o.idType, "type", "", "ID type from --registry that sets the prefix, alphabet, and length")
+	cmd.Flags().StringVar(&o.registryPath, "registry", "", "YAML file defining the ID types accepted by --type")
+	cmd.Flags().StringVar(&o.profile, "profile", "", "Profile from the configuration file that sets the alphabet, length, prefix, source, and format")
+	cmd.Flags().IntVarP(&o.Count, "count", "c", 1, "Number of Nano IDs to generate (0 streams until interrupted)")
+	cmd.Flags().BoolVarP(&o.verbose, "verbose", "v", false, "Enable verbose output")
+	cmd.Flags().BoolVar(&o.progress, "progress", false, "Show progress, rate, ETA, and bytes written on stderr when it is a terminal")
+	cmd.Flags().StringVar(&o.statsFile, "stats-file", "", "Write the stats block to this file instead of stderr")
+	cmd.Flags().IntVarP(&o.Workers, "workers", "w", runtime.GOMAXPROCS(0), "Number of concurrent generation workers")
+	cmd.Flags().StringVarP(&o.Format, "format", "f", generate.FormatText, "Output format: "+strings.Join(generate.Formats(), ", "))
+	config.MarkFlagValues(cmd, "format", generate.Formats()...)
+	cmd.Flags().StringVarP(&o.Source, "source", "s", source.Auto, "Random source: "+strings.Join(source.Names, ", "))
+	cmd.Flags().StringVar(&o.Seed, "seed", "", "Generate reproducible, INSECURE IDs from a seed (64 hex digits or a passphrase)")
+	cmd.Flags().IntVar(&o.DRBG.KeySize, "drbg-key-size", 0, "AES key size in bits for the ctr-drbg source: 128, 192, or 256 (default 256)")
+	cmd.Flags().StringVar(&o.DRBG.Personalization, "drbg-personalization", "", "Personalization string separating this ctr-drbg stream from others")
+	cmd.Flags().BoolVar(&o.DRBG.PredictionResistance, "drbg-prediction-resistance", false, "Reseed the ctr-drbg from system entropy before every read")
+	cmd.Flags().DurationVar(&o.DRBG.ReseedInterval, "drbg-reseed-interval", 0, "Reseed the ctr-drbg after this much time has elapsed")
+	cmd.Flags().Uint64Var(&o.DRBG.ReseedRequests, "drbg-reseed-requests", 0, "Reseed the ctr-drbg after this many reads")
+	cmd.Flags().Uint64Var(&o.DRBG.MaxBytesPerKey, "drbg-max-bytes-per-key", 0, "Rotate the ctr-drbg key after this many output bytes")
+	cmd.Flags().BoolVar(&o.stream, "stream", false, "Generate IDs until interrupted (same as --count 0)")
+	cmd.Flags().StringVar(&o.rate, "rate", "", "Limit output to N IDs per second, e.g. 1000/s, 60/m, or 10/h")
+	cmd.Flags().IntVar(&o.Burst, "burst", 0, "Maximum number of IDs written back-to-back under --rate (default: a tenth of a second's worth)")
+	cmd.Flags().DurationVar(&o.FlushInterval, "flush-interval", generate.DefaultFlushInterval, "How often output is flushed while streaming or rate limiting")
+	cmd.Flags().BoolVar(&o.Unique, "unique", false, "Guarantee that no ID is emitted twice by regenerating duplicates")
+	cmd.Flags().IntVar(&o.UniqueMemory, "unique-memory", generate.DefaultUniqueMemory, "Number of IDs tracked exactly in memory under --unique")
+	cmd.Flags().StringVar(&o.UniqueSpillDir, "unique-spill-dir", "", "Directory to spill tracked IDs to once --unique-memory is exceeded, instead of using a Bloom filter")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", "", "Write IDs to this file instead of stdout")
+	cmd.Flags().IntVar(&o.SplitLines, "split-lines", 0, "Start a new --output file after this many IDs")
+	cmd.Flags().StringVar(&o.splitSize, "split-size", "", "Start a new --output file once it reaches this size, e.g. 1GiB")
+	cmd.Flags().BoolVar(&o.Manifest, "manifest", false, "Write a sidecar manifest listing each --output file's IDs, lines, and SHA-256")
+	cmd.Flags().BoolVar(&o.Force, "force", false, "Overwrite existing --output files")
+	cmd.Flags().StringVar(&o.Compression, "compress", "", "Compress output with: "+strings.Join(generate.Compressions(), ", "))
+	cmd.MarkFlagsMutuallyExclusive("alphabet", "preset")
+	cmd.MarkFlagsMutuallyExclusive("type", "alphabet", "preset")
+	cmd.MarkFlagsMutuallyExclusive("type", "id-length")
+	cmd.MarkFlagsMutuallyExclusive("type", "prefix")
+	cmd.MarkFlagsMutuallyExclusive("type", "checksum")
+	cmd.MarkFlagsRequiredTogether("type", "registry")
+	cmd.MarkFlagsMutuallyExclusive("type", "profile")
+	cmd.MarkFlagsMutuallyExclusive("seed", "source")
+	cmd.MarkFlagsMutuallyExclusive("seed", "sortable")
+	cmd.MarkFlagsMutuallyExclusive("split-lines", "split-size")
 
 	return cmd
 }
 
-// runGenerate is the main execution function for the generate command
-func runGenerate(cmd *cobra.Command, _ []string) error {
-	// Validate id-length
-	if idLength <= 0 {
+// run is the main execution function for the generate command. It resolves the
+// flags that only the command line knows about and hands the rest to generate.Run.
+func (o *options) run(cmd *cobra.Command, _ []string) error {
+	opts := o.Options
+
+	// Validate the flags whose zero value generate.Run would replace with a default
+	if opts.Length <= 0 {
 		return writeString(cmd, "--id-length must be a positive integer")
 	}
+	if opts.Workers <= 0 {
+		return writeString(cmd, "--workers must be a positive integer")
+	}
+	if opts.FlushInterval <= 0 {
+		return writeString(cmd, "--flush-interval must be positive")
+	}
+	if opts.UniqueMemory <= 0 {
+		return writeString(cmd, "--unique-memory must be a positive integer")
+	}
 
-	// Validate count
-	if count <= 0 {
-		return writeString(cmd, "--count must be a positive integer")
+	// Resolve the ID type from the registry
+	if o.idType != "" {
+		reg, err := registry.Load(o.registryPath)
+		if err != nil {
+			return writeError(cmd, "invalid --registry", err)
+		}
+		t, err := reg.Lookup(o.idType)
+		if err != nil {
+			return writeError(cmd, "invalid --type", err)
+		}
+		opts.Alphabet, opts.Length, opts.Prefix, opts.Checksum = t.Alphabet, t.Length, t.Lead(), t.Checksum
 	}
 
-	if fips140.Enabled() {
-		_, _ = fmt.Fprintln(cmd.OutOrStderr(), "FIPS 140 mode is enabled; Nano ID generation is using a FIPS 140 compliant AES-CTR DRBG source.")
+	// Validate streaming
+	if o.stream {
+		if cmd.Flags().Changed("count") && opts.Count != 0 {
+			return writeString(cmd, "--stream cannot be combined with a non-zero --count")
+		}
+		opts.Count = 0
 	}
+	if opts.Unique && opts.Count == 0 {
+		return writeString(cmd, "--unique cannot be combined with --stream or --count 0")
+	}
+	opts.Stream = opts.Count == 0
 
-	// Configure the Nano ID generator using ConfigOptions
-	var configOpts []nanoid.Option
-	configOpts = append(configOpts, nanoid.WithLengthHint(uint16(idLength)))
+	// Validate rate limiting
+	if o.rate != "" {
+		perSecond, err := generate.ParseRate(o.rate)
+		if err != nil {
+			return writeError(cmd, "invalid --rate", err)
+		}
+		opts.Rate = perSecond
+	}
 
-	if alphabet != nanoid.DefaultAlphabet {
-		configOpts = append(configOpts, nanoid.WithAlphabet(alphabet))
-		if verbose {
-			_, _ = fmt.Fprintln(cmd.OutOrStderr(), "Custom alphabet provided. Initializing custom generator.")
+	// Validate file output
+	if opts.Output == "" {
+		for _, name := range []string{"split-lines", "split-size", "manifest", "force"} {
+			if cmd.Flags().Changed(name) {
+				return writeString(cmd, "--"+name+" requires --output")
+			}
 		}
 	}
+	if o.splitSize != "" {
+		splitBytes, err := humanize.ParseBytes(o.splitSize)
+		if err != nil {
+			return writeError(cmd, "invalid --split-size", err)
+		}
+		if splitBytes == 0 {
+			return writeString(cmd, "--split-size must be positive")
+		}
+		opts.SplitSize = splitBytes
+	}
 
-	// Initialize the Nano ID generator with the configured options
-	generator, err := nanoid.NewGenerator(configOpts...)
-	if err != nil {
-		return writeError(cmd, "failed to initialize Nano ID generator", err)
+	if fips140.Enabled() {
+		_, _ = fmt.Fprintln(cmd.OutOrStderr(), "FIPS 140 mode is enabled; Nano ID generation is using a FIPS 140 compliant AES-CTR DRBG source.")
+	}
+	if opts.Seed != "" {
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "WARNING: --seed generates predictable IDs that are NOT secure. Use them only for tests and fixtures.")
+	}
+	if o.verbose && (opts.Preset != "" || opts.Alphabet != nanoid.DefaultAlphabet) {
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "Custom alphabet provided. Initializing custom generator.")
 	}
 
-	// Use a buffered writer for efficient writing
-	writer := bufio.NewWriter(cmd.OutOrStdout())
+	// Streams also stop cleanly when their output pipe is closed
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts.Count == 0 {
+		defer notifyPipe()()
+	}
 
-	// Generate and write the specified number of Nano IDs
-	start := time.Now()
+	// The progress line is only drawn on a terminal, so redirected stderr stays clean
+	if o.progress && isTerminal(cmd.ErrOrStderr()) {
+		opts.Progress = newProgress(cmd.ErrOrStderr())
+	}
 
-	// Generate and write the specified number of Nano IDs
-	for i := 0; i < count; i++ {
-		var id nanoid.ID
-		id, err = generator.NewWithLength(idLength)
-		if err != nil {
-			return writeError(cmd, "error generating Nano ID", err)
+	stats, err := generate.Run(ctx, opts, cmd.OutOrStdout())
+	if opts.Progress != nil {
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr())
+	}
+	if err != nil {
+		var optErr *generate.OptionError
+		if errors.As(err, &optErr) {
+			if optErr.Err != nil {
+				return writeError(cmd, "invalid --"+optErr.Option, optErr.Err)
+			}
+			return writeString(cmd, "--"+optErr.Error())
 		}
-
-		_, err = writer.WriteString(id.String() + "\n")
-		if err != nil {
-			return writeError(cmd, "error generating Nano ID", err)
+		if errors.Is(err, interrupt.ErrInterrupted) || errors.Is(err, interrupt.ErrTimeout) {
+			// The IDs written so far are complete and reported like a stream's; the exit
+			// status reports why the batch is short.
+			cmd.SilenceUsage = true
+			if err := o.reportStats(cmd, stats, opts.Compression); err != nil {
+				return err
+			}
+			return fmt.Errorf("generation stopped after %d of %d IDs: %w", stats.IDs, opts.Count, err)
 		}
+		return writeError(cmd, "error generating Nano ID", err)
 	}
 
-	duration := time.Since(start)
+	if err := o.reportStats(cmd, stats, opts.Compression); err != nil {
+		return err
+	}
 
-	err = writer.Flush()
-	if err != nil {
-		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error flushing writer: %v\n", err)
+	// A stream ends when it is interrupted or times out, and exits with the same status as a batch
+	if opts.Count == 0 && ctx.Err() != nil {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("stream stopped after %d IDs: %w", stats.IDs, context.Cause(ctx))
 	}
 
-	if verbose {
-		// Gather memory stats
-		var memStats runtime.MemStats
-		runtime.ReadMemStats(&memStats)
+	return nil
+}
+
+// reportStats writes the stats of a run to --stats-file or, with --verbose, to stderr.
+// Stats never go to stdout, which holds only the generated IDs.
+func (o *options) reportStats(cmd *cobra.Command, stats generate.Stats, compression string) error {
+	if o.statsFile != "" {
+		var buf bytes.Buffer
+		writeStats(&buf, stats, compression)
+		if err := os.WriteFile(o.statsFile, buf.Bytes(), 0o644); err != nil {
+			return writeError(cmd, "error writing --stats-file", err)
+		}
+	} else if o.verbose {
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr())
+		writeStats(cmd.ErrOrStderr(), stats, compression)
+	}
+	return nil
+}
+
+// writeStats prints the verbose stats block describing a run.
+func writeStats(w io.Writer, stats generate.Stats, compression string) {
+	// Gather memory stats
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
 
-		// Derived stats
-		average := duration / time.Duration(count)
-		throughput := float64(count) / duration.Seconds()
-		estimatedBytes := count * (idLength + 1) // +1 for newline
-		entropyPerChar := math.Log2(float64(len(alphabet)))
-		estimatedEntropy := entropyPerChar * float64(idLength)
+	// Derived stats
+	average := stats.Duration / time.Duration(max(stats.IDs, 1))
+	throughput := float64(stats.IDs) / stats.Duration.Seconds()
 
-		// Print stats
-		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "")
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Start Time..............: %s\n", start.Format(time.RFC3339))
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Total IDs generated.....: %d\n", count)
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Total time taken........: %s\n", duration)
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Average time per ID.....: %s\n", average)
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Throughput..............: %.2f IDs/sec\n", throughput)
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Estimated output size...: %s\n", humanize.Bytes(uint64(estimatedBytes)))
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Estimated entropy per ID: %.2f bits\n", estimatedEntropy)
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Memory used.............: %.2f MiB\n", float64(memStats.Alloc)/(1024*1024))
+	// Print stats
+	_, _ = fmt.Fprintf(w, "Start Time..............: %s\n", stats.Start.Format(time.RFC3339))
+	_, _ = fmt.Fprintf(w, "Random source...........: %s\n", stats.Source)
+	_, _ = fmt.Fprintf(w, "Total IDs generated.....: %d\n", stats.IDs)
+	_, _ = fmt.Fprintf(w, "Total time taken........: %s\n", stats.Duration)
+	_, _ = fmt.Fprintf(w, "Average time per ID.....: %s\n", average)
+	_, _ = fmt.Fprintf(w, "Throughput..............: %.2f IDs/sec\n", throughput)
+	if compression != "" {
+		_, _ = fmt.Fprintf(w, "%s: %s\n", statsLabel("Raw output size"), humanize.Bytes(uint64(stats.Bytes)))
+		_, _ = fmt.Fprintf(w, "%s: %s (%s, %.1f%% of raw)\n", statsLabel("Compressed output size"),
+			humanize.Bytes(uint64(stats.CompressedBytes)), compression, 100*float64(stats.CompressedBytes)/float64(max(stats.Bytes, 1)))
+	} else {
+		_, _ = fmt.Fprintf(w, "Estimated output size...: %s\n", humanize.Bytes(uint64(stats.Bytes)))
 	}
+	_, _ = fmt.Fprintf(w, "Estimated entropy per ID: %.2f bits\n", stats.Entropy())
+	if stats.TimestampWidth > 0 {
+		_, _ = fmt.Fprintf(w, "%s: %d (excluded from entropy)\n", statsLabel("Timestamp characters"), stats.TimestampWidth)
+	}
+	if stats.ChecksumSize > 0 {
+		_, _ = fmt.Fprintf(w, "%s: %d (%s, excluded from entropy)\n", statsLabel("Check characters"), stats.ChecksumSize, stats.Checksum)
+	}
+	_, _ = fmt.Fprintf(w, "Memory used.............: %.2f MiB\n", float64(memStats.Alloc)/(1024*1024))
+	if stats.UniqueMode != "" {
+		_, _ = fmt.Fprintf(w, "%s: %d (%s)\n", statsLabel("Collisions regenerated"), stats.Collisions, stats.UniqueMode)
+	}
+	_, _ = fmt.Fprintf(w, "Workers.................: %d\n", len(stats.Workers))
+	for i, ws := range stats.Workers {
+		label := statsLabel(fmt.Sprintf("Worker %d", i+1))
+		_, _ = fmt.Fprintf(w, "%s: %d IDs, %.2f IDs/sec\n", label, ws.IDs, ws.Throughput())
+	}
+}
 
-	return nil
+// statsLabel pads name with dots so that it lines up with the other labels in the stats block.
+func statsLabel(name string) string {
+	if len(name) >= statsLabelWidth {
+		return name
+	}
+	return name + strings.Repeat(".", statsLabelWidth-len(name))
 }
 
 func writeError(cmd *cobra.Command, msg string, err error) error {
@@ -8,14 +8,34 @@ package generate
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
 	"crypto/fips140"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/sixafter/nanoid-cli/internal/checksum"
+	"github.com/sixafter/nanoid-cli/internal/interrupt"
+	"github.com/sixafter/nanoid-cli/internal/sortable"
+	"github.com/sixafter/nanoid-cli/internal/source"
+	"github.com/sixafter/nanoid-cli/pkg/generate"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
 )
 
 func TestGenerateCommand_Default(t *testing.T) {
@@ -85,28 +105,359 @@ func TestGenerateCommand_CustomAlphabet(t *testing.T) {
 	}
 }
 
+func TestGenerateCommand_Preset(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--preset", "a-f,0-9,-lookalikes", "--count", "20"})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	err := cmd.Execute()
+	is.NoError(err, "Expected no error on generate command with a preset expression")
+
+	for _, id := range strings.Split(strings.TrimSpace(outBuf.String()), "\n") {
+		is.Len(id, 21)
+		is.Empty(strings.Trim(id, "abcdef346789"), "Expected characters in ID to match the expanded preset")
+	}
+}
+
+func TestGenerateCommand_InvalidPreset(t *testing.T) {
+	is := assert.New(t)
+
+	for _, args := range [][]string{
+		{"--preset", "base64"},
+		{"--preset", "numeric", "--alphabet", "abc"},
+	} {
+		cmd := NewGenerateCommand()
+		cmd.SetArgs(args)
+
+		var outBuf, errBuf bytes.Buffer
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&errBuf)
+
+		is.Error(cmd.Execute(), strings.Join(args, " "))
+	}
+}
+
+func TestGenerateCommand_Prefix(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--prefix", "usr_", "--count", "3", "--format", "ndjson"})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	err := cmd.Execute()
+	is.NoError(err)
+
+	for _, line := range strings.Split(strings.TrimSpace(outBuf.String()), "\n") {
+		var r struct {
+			ID     string `json:"id"`
+			Length int    `json:"length"`
+		}
+		is.NoError(json.Unmarshal([]byte(line), &r))
+		is.True(strings.HasPrefix(r.ID, "usr_"), "Expected the prefix in every format")
+		is.Len(r.ID, 25)
+		is.Equal(25, r.Length)
+	}
+}
+
+func TestGenerateCommand_Type(t *testing.T) {
+	is := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "types.yaml")
+	is.NoError(os.WriteFile(path, []byte("types:\n  order:\n    prefix: ord\n    preset: numeric\n    length: 8\n"), 0o600))
+
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--registry", path, "--type", "order", "--count", "5"})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	err := cmd.Execute()
+	is.NoError(err)
+
+	for _, id := range strings.Split(strings.TrimSpace(outBuf.String()), "\n") {
+		body, ok := strings.CutPrefix(id, "ord_")
+		is.True(ok, "Expected the type's prefix and separator")
+		is.Len(body, 8)
+		is.Empty(strings.Trim(body, "0123456789"))
+	}
+}
+
+func TestGenerateCommand_Checksum(t *testing.T) {
+	is := assert.New(t)
+
+	sum, err := checksum.New(checksum.Weighted, "0123456789")
+	is.NoError(err)
+
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--preset", "numeric", "--id-length", "8", "--checksum", "weighted", "--prefix", "x_", "--count", "20", "--verbose"})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err = cmd.Execute()
+	is.NoError(err)
+
+	stats := errBuf.String()
+	for _, id := range strings.Split(strings.TrimSpace(outBuf.String()), "\n") {
+		id, ok := strings.CutPrefix(id, "x_")
+		is.True(ok, "Expected the prefix before the check characters")
+		is.Len(id, 10)
+
+		body, err := sum.Verify(id)
+		is.NoError(err, id)
+		is.Len(body, 8)
+	}
+
+	// The check characters add no entropy: 8 decimal digits carry 26.58 bits
+	is.Contains(stats, "Estimated entropy per ID: 26.58 bits")
+	is.Contains(stats, "Check characters........: 2 (weighted, excluded from entropy)")
+}
+
+func TestGenerateCommand_Sortable(t *testing.T) {
+	is := assert.New(t)
+
+	enc, err := sortable.NewEncoding("0123456789abcdef")
+	is.NoError(err)
+
+	before := time.Now().Truncate(time.Millisecond)
+
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--sortable", "--preset", "hex-lower", "--id-length", "8", "--count", "500", "--workers", "4"})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	err = cmd.Execute()
+	is.NoError(err)
+
+	ids := strings.Split(strings.TrimSpace(outBuf.String()), "\n")
+	is.Len(ids, 500)
+	is.True(slices.IsSorted(ids), "Expected IDs to sort in the order they were written")
+	is.Len(slices.Compact(slices.Clone(ids)), 500)
+
+	for _, id := range ids {
+		is.Len(id, enc.Width()+8)
+
+		stamp, err := enc.Decode(id)
+		is.NoError(err)
+		is.False(stamp.Time.Before(before), "Expected the timestamp of %s to be recent", id)
+	}
+}
+
+func TestGenerateCommand_TypeChecksum(t *testing.T) {
+	is := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "types.yaml")
+	is.NoError(os.WriteFile(path, []byte("types:\n  card:\n    prefix: crd\n    preset: numeric\n    length: 10\n    checksum: luhn\n"), 0o600))
+
+	sum, err := checksum.New(checksum.Luhn, "0123456789")
+	is.NoError(err)
+
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--registry", path, "--type", "card", "--count", "5"})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	err = cmd.Execute()
+	is.NoError(err)
+
+	for _, id := range strings.Split(strings.TrimSpace(outBuf.String()), "\n") {
+		body, ok := strings.CutPrefix(id, "crd_")
+		is.True(ok)
+		is.Len(body, 11)
+
+		_, err := sum.Verify(body)
+		is.NoError(err, id)
+	}
+}
+
+func TestGenerateCommand_InvalidType(t *testing.T) {
+	is := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "types.yaml")
+	is.NoError(os.WriteFile(path, []byte("types:\n  order:\n    prefix: ord\n"), 0o600))
+
+	tests := map[string][]string{
+		"unknown type":      {"--registry", path, "--type", "invoice"},
+		"missing registry":  {"--type", "order"},
+		"bad registry":      {"--registry", filepath.Join(t.TempDir(), "missing.yaml"), "--type", "order"},
+		"type and length":   {"--registry", path, "--type", "order", "--id-length", "5"},
+		"type and prefix":   {"--registry", path, "--type", "order", "--prefix", "x_"},
+		"type and checksum": {"--registry", path, "--type", "order", "--checksum", "luhn"},
+		"unknown checksum":  {"--checksum", "crc"},
+	}
+
+	for name, args := range tests {
+		cmd := NewGenerateCommand()
+		cmd.SetArgs(args)
+
+		var outBuf, errBuf bytes.Buffer
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&errBuf)
+
+		is.Error(cmd.Execute(), name)
+	}
+}
+
 func TestGenerateCommand_Verbose(t *testing.T) {
 	is := assert.New(t)
 
 	// Set up command
 	cmd := NewGenerateCommand()
-	cmd.SetArgs([]string{"--id-length", "30", "--count", "10", "--verbose"})
+	cmd.SetArgs([]string{"--id-length", "30", "--count", "10", "--workers", "2", "--verbose"})
 
 	// Capture output
-	var outBuf bytes.Buffer
+	var outBuf, errBuf bytes.Buffer
 	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
 
 	// Execute command
 	err := cmd.Execute()
 	is.NoError(err, "Expected no error on generate command with custom length")
 
-	// Verify output contains one ID of length 30
-	output := strings.TrimSpace(outBuf.String())
+	// Verify stdout holds only the IDs
+	lines := strings.Split(strings.TrimSpace(outBuf.String()), "\n")
+	is.Equal(10, len(lines), "Expected stdout to contain only the 10 IDs")
+	for _, line := range lines {
+		is.Len(line, 30)
+	}
+
+	// Verify the stats went to stderr
+	stats := strings.Split(strings.TrimSpace(errBuf.String()), "\n")
+	is.Equal(12, len(stats), "Expected stderr to contain 12 verbose messages")
+	is.Contains(errBuf.String(), "Workers.................: 2")
+	is.Contains(errBuf.String(), "Worker 1................: 5 IDs")
+	is.Contains(errBuf.String(), "Worker 2................: 5 IDs")
+}
+
+func TestGenerateCommand_StatsFile(t *testing.T) {
+	is := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "stats.txt")
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--count", "5", "--verbose", "--stats-file", path})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+	is.NoError(cmd.Execute())
+
+	is.Len(strings.Split(strings.TrimSpace(outBuf.String()), "\n"), 5, "Expected stdout to contain only the IDs")
+	is.Empty(errBuf.String(), "Expected the stats to go to the file instead of stderr")
+
+	data, err := os.ReadFile(path)
+	is.NoError(err)
+	is.True(strings.HasPrefix(string(data), "Start Time..............: "))
+	is.Contains(string(data), "Total IDs generated.....: 5")
+
+	// An unwritable stats file is reported after the IDs are written
+	cmd = NewGenerateCommand()
+	cmd.SetArgs([]string{"--count", "1", "--stats-file", filepath.Join(t.TempDir(), "missing", "stats.txt")})
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+	is.ErrorContains(cmd.Execute(), "error writing --stats-file")
+}
+
+func TestGenerateCommand_Progress(t *testing.T) {
+	is := assert.New(t)
+
+	// A buffer is not a terminal, so no progress line is drawn
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--count", "100", "--progress"})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+	is.NoError(cmd.Execute())
+
+	is.Len(strings.Split(strings.TrimSpace(outBuf.String()), "\n"), 100)
+	is.Empty(errBuf.String(), "Expected no progress on a stderr that is not a terminal")
+	is.False(isTerminal(&errBuf))
+}
+
+func TestProgressLine(t *testing.T) {
+	is := assert.New(t)
+
+	batch := progressLine(generate.Progress{IDs: 2500, Count: 10000, Bytes: 55000, Elapsed: 2 * time.Second})
+	is.Equal("[======                  ]  25%  2,500/10,000 IDs  1,250 IDs/s  55 kB  ETA 6s", batch)
+
+	stream := progressLine(generate.Progress{IDs: 12000, Bytes: 264000, Elapsed: 3 * time.Second})
+	is.Equal("12,000 IDs  4,000 IDs/s  264 kB  3s elapsed", stream)
+}
+
+func TestGenerateCommand_Workers(t *testing.T) {
+	is := assert.New(t)
+
+	// Set up command with more IDs than a single batch so that every worker sends several batches
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--count", "5000", "--workers", "4"})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	err := cmd.Execute()
+	is.NoError(err, "Expected no error on generate command with multiple workers")
+
+	// Every line must be a complete ID; interleaved writes would produce lines of the wrong length
+	ids := strings.Split(strings.TrimSpace(outBuf.String()), "\n")
+	is.Len(ids, 5000, "Expected 5000 IDs in the output")
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		is.Len(id, 21, "Expected ID of default length 21")
+		seen[id] = struct{}{}
+	}
+	is.Len(seen, 5000, "Expected all IDs to be distinct")
+}
+
+func TestGenerateCommand_Independent(t *testing.T) {
+	is := assert.New(t)
 
-	// Split on \n to get individual lines
-	lines := strings.Split(output, "\n")
+	// Commands created by separate calls never share flag values, even when run in parallel.
+	lengths := []int{8, 12, 16, 20}
+	outputs := make([]bytes.Buffer, len(lengths))
+	commands := make([]*cobra.Command, len(lengths))
+	for i, length := range lengths {
+		commands[i] = NewGenerateCommand()
+		commands[i].SetArgs([]string{"--id-length", fmt.Sprint(length), "--count", "50"})
+		commands[i].SetOut(&outputs[i])
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(commands))
+	for i, cmd := range commands {
+		wg.Go(func() { errs[i] = cmd.Execute() })
+	}
+	wg.Wait()
+
+	for i, length := range lengths {
+		is.NoError(errs[i])
+		for _, id := range strings.Split(strings.TrimSpace(outputs[i].String()), "\n") {
+			is.Len(id, length)
+		}
+	}
+}
+
+func TestGenerateCommand_InvalidWorkers(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--workers", "0"})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
 
-	is.Equal(19, len(lines), "Expected output to contain 19 lines (10 IDs of length 30 + 10 verbose messages)")
+	err := cmd.Execute()
+	is.Error(err, "Expected an error on invalid worker count")
+	is.Contains(errBuf.String(), "--workers must be a positive integer")
 }
 
 func TestGenerateCommand_ErrorOutput(t *testing.T) {
@@ -135,6 +486,725 @@ func TestGenerateCommand_ErrorOutput(t *testing.T) {
 	is.NotEmpty(stdoutOutput, "Expected output showing usage.")
 }
 
+func TestGenerateCommand_Formats(t *testing.T) {
+	is := assert.New(t)
+
+	// decode parses the output of each format back into the list of IDs it contains
+	decode := map[string]func(string) ([]string, error){
+		generate.FormatText: func(out string) ([]string, error) {
+			return strings.Split(strings.TrimSpace(out), "\n"), nil
+		},
+		generate.FormatJSON: func(out string) ([]string, error) {
+			var ids []string
+			err := json.Unmarshal([]byte(out), &ids)
+			return ids, err
+		},
+		generate.FormatNDJSON: func(out string) ([]string, error) {
+			var ids []string
+			for i, line := range strings.Split(strings.TrimSpace(out), "\n") {
+				var rec struct {
+					ID           string `json:"id"`
+					Length       int    `json:"length"`
+					AlphabetHash string `json:"alphabet_hash"`
+					Index        int    `json:"index"`
+				}
+				if err := json.Unmarshal([]byte(line), &rec); err != nil {
+					return nil, err
+				}
+				if rec.Index != i || rec.Length != len(rec.ID) || rec.AlphabetHash == "" {
+					return nil, fmt.Errorf("unexpected record %+v", rec)
+				}
+				ids = append(ids, rec.ID)
+			}
+			return ids, nil
+		},
+		generate.FormatCSV: func(out string) ([]string, error) {
+			rows, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+			if err != nil {
+				return nil, err
+			}
+			if strings.Join(rows[0], ",") != "id,length,alphabet_hash,index" {
+				return nil, fmt.Errorf("unexpected header %v", rows[0])
+			}
+			var ids []string
+			for _, row := range rows[1:] {
+				ids = append(ids, row[0])
+			}
+			return ids, nil
+		},
+		generate.FormatYAML: func(out string) ([]string, error) {
+			var ids []string
+			err := yaml.Unmarshal([]byte(out), &ids)
+			return ids, err
+		},
+	}
+
+	for _, f := range generate.Formats() {
+		t.Run(f, func(t *testing.T) {
+			cmd := NewGenerateCommand()
+			cmd.SetArgs([]string{"--count", "5", "--workers", "2", "--format", f})
+
+			var outBuf bytes.Buffer
+			cmd.SetOut(&outBuf)
+
+			err := cmd.Execute()
+			is.NoError(err, "Expected no error on generate command with format %s", f)
+
+			ids, err := decode[f](outBuf.String())
+			is.NoError(err, "Expected %s output to be decodable", f)
+			is.Len(ids, 5, "Expected five IDs in %s output", f)
+			for _, id := range ids {
+				is.Len(id, 21, "Expected ID of default length 21")
+			}
+		})
+	}
+}
+
+func TestGenerateCommand_InvalidFormat(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--format", "xml"})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err := cmd.Execute()
+	is.Error(err, "Expected an error on unsupported format")
+	is.Contains(errBuf.String(), "--format must be one of: text, json, ndjson, csv, yaml")
+}
+
+func TestGenerateCommand_Sources(t *testing.T) {
+	for _, name := range []string{source.CryptoRand, source.ChaCha20, source.CTRDRBG} {
+		t.Run(name, func(t *testing.T) {
+			is := assert.New(t)
+
+			if name == source.ChaCha20 && fips140.Enabled() {
+				t.Skip("ChaCha20 is not permitted in FIPS 140 mode")
+			}
+
+			cmd := NewGenerateCommand()
+			cmd.SetArgs([]string{"--count", "3", "--source", name, "--verbose"})
+
+			var outBuf, errBuf bytes.Buffer
+			cmd.SetOut(&outBuf)
+			cmd.SetErr(&errBuf)
+
+			err := cmd.Execute()
+			is.NoError(err, "Expected no error on generate command with source %s", name)
+			is.Contains(errBuf.String(), "Random source...........: "+name)
+		})
+	}
+}
+
+func TestGenerateCommand_InvalidSource(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--source", "dev-random"})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err := cmd.Execute()
+	is.ErrorIs(err, source.ErrUnknownSource)
+	is.Contains(errBuf.String(), "invalid --source")
+}
+
+func TestGenerateCommand_DRBGOptions(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{
+		"--count", "2",
+		"--source", source.CTRDRBG,
+		"--drbg-key-size", "192",
+		"--drbg-personalization", "tenant-a",
+		"--drbg-reseed-interval", "1m",
+		"--drbg-reseed-requests", "100",
+	})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	err := cmd.Execute()
+	is.NoError(err, "Expected no error on generate command with DRBG options")
+	is.Len(strings.Split(strings.TrimSpace(outBuf.String()), "\n"), 2)
+}
+
+func TestGenerateCommand_InvalidDRBGOptions(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"key size": {
+			args: []string{"--source", source.CTRDRBG, "--drbg-key-size", "64"},
+			want: "key size must be 128, 192, or 256 bits",
+		},
+		"wrong source": {
+			args: []string{"--source", source.CryptoRand, "--drbg-personalization", "tenant-a"},
+			want: "DRBG options require the ctr-drbg source",
+		},
+		"prediction resistance with reseed": {
+			args: []string{"--source", source.CTRDRBG, "--drbg-prediction-resistance", "--drbg-reseed-interval", "1s"},
+			want: "cannot be combined with a reseed interval",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := assert.New(t)
+
+			cmd := NewGenerateCommand()
+			cmd.SetArgs(tc.args)
+
+			var outBuf, errBuf bytes.Buffer
+			cmd.SetOut(&outBuf)
+			cmd.SetErr(&errBuf)
+
+			err := cmd.Execute()
+			is.ErrorIs(err, source.ErrInvalidDRBGOptions)
+			is.ErrorContains(err, tc.want)
+		})
+	}
+}
+
+func TestGenerateCommand_Stream(t *testing.T) {
+	is := assert.New(t)
+
+	// Cancelling the context with ErrInterrupted stands in for SIGINT
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	time.AfterFunc(300*time.Millisecond, func() { cancel(interrupt.ErrInterrupted) })
+
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--stream", "--rate", "100/s", "--burst", "1", "--workers", "2", "--format", "ndjson"})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	// An interrupted stream exits with the same status as an interrupted batch
+	err := cmd.ExecuteContext(ctx)
+	is.ErrorIs(err, interrupt.ErrInterrupted)
+	is.ErrorContains(err, "stream stopped after")
+	is.Equal(interrupt.ExitInterrupted, interrupt.ExitCode(err))
+	is.NotContains(errBuf.String(), "Usage:")
+
+	// Every line is a complete record; the rate keeps the total well below the unlimited output
+	lines := strings.Split(strings.TrimSpace(outBuf.String()), "\n")
+	is.NotEmpty(lines)
+	is.Less(len(lines), 100, "Expected --rate to limit output")
+	for i, line := range lines {
+		var r struct {
+			ID    string `json:"id"`
+			Index int    `json:"index"`
+		}
+		is.NoError(json.Unmarshal([]byte(line), &r), "Expected complete NDJSON lines")
+		is.Len(r.ID, 21)
+		is.Equal(i, r.Index)
+	}
+}
+
+// closedPipe accepts limit bytes and then fails like a pipe whose reader has exited.
+type closedPipe struct {
+	limit int
+}
+
+func (p *closedPipe) Write(b []byte) (int, error) {
+	if p.limit <= 0 {
+		return 0, syscall.EPIPE
+	}
+	p.limit -= len(b)
+	return len(b), nil
+}
+
+func TestGenerateCommand_StreamClosedPipe(t *testing.T) {
+	is := assert.New(t)
+
+	// A stream piped into a consumer that exits early, such as head, ends without error
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--stream"})
+	cmd.SetOut(&closedPipe{limit: 1 << 16})
+
+	var errBuf bytes.Buffer
+	cmd.SetErr(&errBuf)
+
+	is.NoError(cmd.ExecuteContext(context.Background()))
+	is.Empty(errBuf.String())
+}
+
+func TestGenerateCommand_Interrupted(t *testing.T) {
+	is := assert.New(t)
+
+	// Cancelling the context with ErrInterrupted stands in for SIGINT
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	time.AfterFunc(200*time.Millisecond, func() { cancel(interrupt.ErrInterrupted) })
+
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--count", "1000", "--rate", "50/s", "--burst", "1", "--format", "json", "--verbose"})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err := cmd.ExecuteContext(ctx)
+	is.ErrorIs(err, interrupt.ErrInterrupted)
+	is.ErrorContains(err, "of 1000 IDs")
+	is.Equal(interrupt.ExitInterrupted, interrupt.ExitCode(err))
+	is.NotContains(outBuf.String(), "Usage:")
+
+	// The IDs written before the interrupt form a complete JSON document
+	var ids []string
+	is.NoError(json.Unmarshal(outBuf.Bytes(), &ids), "Expected a closed JSON array")
+	is.NotEmpty(ids)
+	is.Less(len(ids), 1000)
+
+	// The stats describe the IDs written before the interrupt
+	is.Contains(errBuf.String(), fmt.Sprintf("Total IDs generated.....: %d\n", len(ids)))
+
+	// An interrupted --output file is not published
+	path := filepath.Join(t.TempDir(), "ids.txt")
+	statsPath := filepath.Join(t.TempDir(), "stats.txt")
+	ctx, cancel = context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	time.AfterFunc(200*time.Millisecond, func() { cancel(interrupt.ErrTimeout) })
+
+	cmd = NewGenerateCommand()
+	cmd.SetArgs([]string{"--count", "1000", "--rate", "50/s", "--burst", "1", "--output", path, "--stats-file", statsPath})
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err = cmd.ExecuteContext(ctx)
+	is.ErrorIs(err, interrupt.ErrTimeout)
+	entries, err := os.ReadDir(filepath.Dir(path))
+	is.NoError(err)
+	is.Empty(entries, "Expected no partial file to be left behind")
+
+	stats, err := os.ReadFile(statsPath)
+	is.NoError(err)
+	is.Contains(string(stats), "Total IDs generated")
+}
+
+func TestGenerateCommand_InvalidStream(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"negative count", []string{"--count", "-1"}, "--count must not be negative"},
+		{"stream with count", []string{"--stream", "--count", "5"}, "--stream cannot be combined with a non-zero --count"},
+		{"bad rate", []string{"--rate", "fast"}, "invalid --rate"},
+		{"bad rate unit", []string{"--rate", "10/d"}, "invalid --rate"},
+		{"negative burst", []string{"--rate", "10", "--burst", "-1"}, "--burst must not be negative"},
+		{"zero flush interval", []string{"--stream", "--flush-interval", "0s"}, "--flush-interval must be positive"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			is := assert.New(t)
+
+			cmd := NewGenerateCommand()
+			cmd.SetArgs(tc.args)
+
+			var outBuf, errBuf bytes.Buffer
+			cmd.SetOut(&outBuf)
+			cmd.SetErr(&errBuf)
+
+			err := cmd.Execute()
+			is.ErrorContains(err, tc.want)
+		})
+	}
+}
+
+func TestGenerateCommand_Unique(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		mode string
+	}{
+		{"exact", nil, "exact"},
+		{"bloom", []string{"--unique-memory", "100"}, "bloom"},
+		{"spill", []string{"--unique-memory", "100", "--unique-spill-dir", t.TempDir()}, "spill"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			is := assert.New(t)
+
+			// 900 of the 1024 possible IDs guarantees plenty of collisions
+			cmd := NewGenerateCommand()
+			cmd.SetArgs(append([]string{"--alphabet", "01", "--id-length", "10", "--count", "900", "--workers", "3", "--unique", "--verbose"}, tc.args...))
+
+			var outBuf, errBuf bytes.Buffer
+			cmd.SetOut(&outBuf)
+			cmd.SetErr(&errBuf)
+
+			err := cmd.Execute()
+			is.NoError(err)
+
+			stats := errBuf.String()
+			ids := strings.Split(strings.TrimSpace(outBuf.String()), "\n")
+			is.Len(ids, 900)
+
+			seen := make(map[string]struct{}, len(ids))
+			for _, id := range ids {
+				seen[id] = struct{}{}
+			}
+			is.Len(seen, 900, "Expected all IDs to be distinct")
+			is.Regexp(`Collisions regenerated\.\.: [1-9]\d* \(`+tc.mode+`\)`, stats)
+		})
+	}
+}
+
+func TestGenerateCommand_InvalidUnique(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"exceeds keyspace", []string{"--alphabet", "01", "--id-length", "10", "--count", "1025"}, "--count 1025 exceeds the 1024 distinct IDs of length 10 over a 2-character alphabet"},
+		{"stream", []string{"--stream"}, "--unique cannot be combined with --stream or --count 0"},
+		{"memory", []string{"--unique-memory", "0"}, "--unique-memory must be a positive integer"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			is := assert.New(t)
+
+			cmd := NewGenerateCommand()
+			cmd.SetArgs(append([]string{"--unique"}, tc.args...))
+
+			var outBuf, errBuf bytes.Buffer
+			cmd.SetOut(&outBuf)
+			cmd.SetErr(&errBuf)
+
+			err := cmd.Execute()
+			is.ErrorContains(err, tc.want)
+		})
+	}
+}
+
+func TestGenerateCommand_Seed(t *testing.T) {
+	is := assert.New(t)
+
+	run := func(args ...string) (string, string, error) {
+		cmd := NewGenerateCommand()
+		cmd.SetArgs(args)
+
+		var outBuf, errBuf bytes.Buffer
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&errBuf)
+
+		err := cmd.Execute()
+		return outBuf.String(), errBuf.String(), err
+	}
+
+	if fips140.Enabled() {
+		_, _, err := run("--seed", "fixtures")
+		is.ErrorIs(err, source.ErrNotFIPSApproved)
+		return
+	}
+
+	out, stderr, err := run("--seed", "fixtures", "--count", "3", "--workers", "4")
+	is.NoError(err)
+	is.Contains(stderr, "WARNING: --seed generates predictable IDs that are NOT secure")
+
+	// The stream is pinned so that fixtures stay stable across releases and platforms.
+	is.Equal("YGbixzZMzMnfLCr5SJ23C\nYN3ykxbTp5BJIZxW6Ra4j\nKvABzGhJk1wLpuGnJ1eJw\n", out)
+
+	again, _, err := run("--seed", "fixtures", "--count", "3")
+	is.NoError(err)
+	is.Equal(out, again, "Expected the same seed to reproduce the same IDs")
+
+	other, _, err := run("--seed", "other", "--count", "3")
+	is.NoError(err)
+	is.NotEqual(out, other)
+
+	// Regenerated duplicates are reproducible too.
+	args := []string{"--seed", "fixtures", "--alphabet", "01", "--id-length", "10", "--count", "900", "--unique"}
+	first, _, err := run(args...)
+	is.NoError(err)
+	second, _, err := run(args...)
+	is.NoError(err)
+	is.Equal(first, second)
+}
+
+func TestGenerateCommand_InvalidSeed(t *testing.T) {
+	is := assert.New(t)
+
+	tests := map[string][]string{
+		"seed and source":   {"--seed", "x", "--source", "crypto-rand"},
+		"seed and sortable": {"--seed", "x", "--sortable"},
+		"seed and drbg":     {"--seed", "x", "--drbg-key-size", "128"},
+	}
+
+	for name, args := range tests {
+		cmd := NewGenerateCommand()
+		cmd.SetArgs(args)
+
+		var outBuf, errBuf bytes.Buffer
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&errBuf)
+
+		is.Error(cmd.Execute(), name)
+	}
+}
+
+func TestGenerateCommand_Output(t *testing.T) {
+	is := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ids.txt")
+
+	run := func(args ...string) (string, error) {
+		cmd := NewGenerateCommand()
+		cmd.SetArgs(args)
+
+		var outBuf, errBuf bytes.Buffer
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&errBuf)
+
+		err := cmd.Execute()
+		return outBuf.String(), err
+	}
+
+	out, err := run("--count", "5", "--output", path, "--manifest")
+	is.NoError(err)
+	is.Empty(out, "Expected IDs to be written to --output instead of stdout")
+
+	data, err := os.ReadFile(path)
+	is.NoError(err)
+	is.Len(strings.Split(strings.TrimSpace(string(data)), "\n"), 5)
+
+	var m generate.Manifest
+	raw, err := os.ReadFile(filepath.Join(dir, "ids.manifest.json"))
+	is.NoError(err)
+	is.NoError(json.Unmarshal(raw, &m))
+	digest := sha256.Sum256(data)
+	is.Equal(generate.Manifest{Format: generate.FormatText, IDs: 5, Files: []generate.ManifestFile{
+		{Path: "ids.txt", IDs: 5, Lines: 5, Bytes: int64(len(data)), SHA256: hex.EncodeToString(digest[:])},
+	}}, m)
+
+	entries, err := os.ReadDir(dir)
+	is.NoError(err)
+	is.Len(entries, 2, "Expected no temporary files to be left behind")
+
+	// Existing files are protected unless --force is given.
+	_, err = run("--count", "1", "--output", path)
+	is.ErrorIs(err, generate.ErrOutputExists)
+	after, err := os.ReadFile(path)
+	is.NoError(err)
+	is.Equal(data, after)
+
+	_, err = run("--count", "2", "--output", path, "--manifest")
+	is.ErrorIs(err, generate.ErrOutputExists, "Expected an existing manifest to be protected")
+
+	_, err = run("--count", "2", "--output", path, "--manifest", "--force")
+	is.NoError(err)
+	after, err = os.ReadFile(path)
+	is.NoError(err)
+	is.Len(strings.Split(strings.TrimSpace(string(after)), "\n"), 2)
+}
+
+func TestGenerateCommand_OutputSplit(t *testing.T) {
+	is := assert.New(t)
+	dir := t.TempDir()
+
+	run := func(args ...string) error {
+		cmd := NewGenerateCommand()
+		cmd.SetArgs(args)
+
+		var outBuf, errBuf bytes.Buffer
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&errBuf)
+
+		return cmd.Execute()
+	}
+
+	// Every file is a complete JSON document, and an exact multiple leaves no empty file.
+	is.NoError(run("--count", "8", "--format", "json", "--split-lines", "4", "--output", filepath.Join(dir, "ids.json"), "--manifest"))
+	var all []string
+	for _, name := range []string{"ids-00001.json", "ids-00002.json"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		is.NoError(err)
+
+		var ids []string
+		is.NoError(json.Unmarshal(data, &ids), name)
+		is.Len(ids, 4)
+		all = append(all, ids...)
+	}
+	is.NoFileExists(filepath.Join(dir, "ids-00003.json"))
+	is.Len(all, 8)
+
+	var m generate.Manifest
+	raw, err := os.ReadFile(filepath.Join(dir, "ids.manifest.json"))
+	is.NoError(err)
+	is.NoError(json.Unmarshal(raw, &m))
+	is.Equal(int64(8), m.IDs)
+	is.Len(m.Files, 2)
+
+	// Rerunning with fewer files removes the stale ones under --force, and only those.
+	other := filepath.Join(dir, "ids-00003.txt")
+	is.NoError(os.WriteFile(filepath.Join(dir, "ids-00003.json"), []byte("[]\n"), 0o644))
+	is.NoError(os.WriteFile(other, []byte("kept\n"), 0o644))
+	is.ErrorIs(run("--count", "4", "--format", "json", "--split-lines", "4", "--output", filepath.Join(dir, "ids.json"), "--manifest"), generate.ErrOutputExists)
+	is.FileExists(filepath.Join(dir, "ids-00002.json"))
+	is.NoError(run("--count", "4", "--format", "json", "--split-lines", "4", "--output", filepath.Join(dir, "ids.json"), "--manifest", "--force"))
+	is.FileExists(filepath.Join(dir, "ids-00001.json"))
+	is.NoFileExists(filepath.Join(dir, "ids-00002.json"))
+	is.NoFileExists(filepath.Join(dir, "ids-00003.json"))
+	is.FileExists(other)
+
+	raw, err = os.ReadFile(filepath.Join(dir, "ids.manifest.json"))
+	is.NoError(err)
+	is.NoError(json.Unmarshal(raw, &m))
+	is.Len(m.Files, 1)
+
+	// Every CSV file starts with a header and stays close to --split-size.
+	is.NoError(run("--count", "200", "--format", "csv", "--split-size", "1KiB", "--output", filepath.Join(dir, "ids.csv")))
+	files, err := filepath.Glob(filepath.Join(dir, "ids-*.csv"))
+	is.NoError(err)
+	is.Greater(len(files), 4)
+
+	rows := 0
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		is.NoError(err)
+		is.Less(len(data), 1024+100, name)
+
+		records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		is.NoError(err)
+		is.Equal("id", records[0][0], name)
+		rows += len(records) - 1
+	}
+	is.Equal(200, rows)
+}
+
+func TestGenerateCommand_InvalidOutput(t *testing.T) {
+	is := assert.New(t)
+	dir := t.TempDir()
+
+	tests := map[string][]string{
+		"split without output":    {"--split-lines", "10"},
+		"manifest without output": {"--manifest"},
+		"force without output":    {"--force"},
+		"lines and size":          {"--output", filepath.Join(dir, "a.txt"), "--split-lines", "10", "--split-size", "1MiB"},
+		"negative lines":          {"--output", filepath.Join(dir, "b.txt"), "--split-lines", "-1"},
+		"invalid size":            {"--output", filepath.Join(dir, "c.txt"), "--split-size", "lots"},
+		"zero size":               {"--output", filepath.Join(dir, "d.txt"), "--split-size", "0"},
+		"missing directory":       {"--output", filepath.Join(dir, "missing", "e.txt")},
+	}
+
+	for name, args := range tests {
+		cmd := NewGenerateCommand()
+		cmd.SetArgs(args)
+
+		var outBuf, errBuf bytes.Buffer
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&errBuf)
+
+		is.Error(cmd.Execute(), name)
+	}
+
+	entries, err := os.ReadDir(dir)
+	is.NoError(err)
+	is.Empty(entries, "Expected failed runs to leave no files behind")
+}
+
+func TestGenerateCommand_Compress(t *testing.T) {
+	is := assert.New(t)
+
+	for _, name := range generate.Compressions() {
+		cmd := NewGenerateCommand()
+		cmd.SetArgs([]string{"--count", "1000", "--compress", name})
+
+		var outBuf, errBuf bytes.Buffer
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&errBuf)
+		is.NoError(cmd.Execute(), name)
+
+		var r io.Reader
+		var err error
+		if name == generate.CompressGzip {
+			r, err = gzip.NewReader(&outBuf)
+		} else {
+			r, err = zlib.NewReader(&outBuf)
+		}
+		is.NoError(err, name)
+		data, err := io.ReadAll(r)
+		is.NoError(err, name)
+		is.Len(strings.Split(strings.TrimSpace(string(data)), "\n"), 1000, name)
+	}
+
+	// Verbose stats report both sizes.
+	path := filepath.Join(t.TempDir(), "ids.txt.gz")
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--count", "1000", "--compress", "gzip", "--output", path, "--verbose"})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+	is.NoError(cmd.Execute())
+
+	is.Contains(errBuf.String(), "Raw output size.........: 22 kB")
+	is.Regexp(`Compressed output size\.\.: 1\d kB \(gzip, 7\d\.\d% of raw\)`, errBuf.String())
+	is.NotContains(errBuf.String(), "Estimated output size")
+}
+
+func TestGenerateCommand_CompressSplit(t *testing.T) {
+	is := assert.New(t)
+	dir := t.TempDir()
+
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--count", "250", "--format", "csv", "--compress", "gzip", "--split-lines", "100",
+		"--output", filepath.Join(dir, "ids.csv.gz"), "--manifest"})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+	is.NoError(cmd.Execute())
+
+	var m generate.Manifest
+	raw, err := os.ReadFile(filepath.Join(dir, "ids.manifest.json"))
+	is.NoError(err)
+	is.NoError(json.Unmarshal(raw, &m))
+	is.Equal(generate.CompressGzip, m.Compression)
+	is.Len(m.Files, 3)
+
+	// Every file is a complete stream on its own, and line counts refer to its contents.
+	for i, f := range m.Files {
+		is.Equal(fmt.Sprintf("ids-%05d.csv.gz", i+1), f.Path)
+
+		data, err := os.ReadFile(filepath.Join(dir, f.Path))
+		is.NoError(err)
+		is.Equal(int64(len(data)), f.Bytes)
+
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		is.NoError(err)
+		records, err := csv.NewReader(r).ReadAll()
+		is.NoError(err)
+		is.Equal("id", records[0][0])
+		is.Equal(f.IDs, int64(len(records)-1))
+		is.Equal(f.Lines, int64(len(records)))
+	}
+}
+
+func TestGenerateCommand_InvalidCompress(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewGenerateCommand()
+	cmd.SetArgs([]string{"--compress", "zstd"})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	is.ErrorContains(cmd.Execute(), "--compress must be one of: gzip, zlib")
+}
+
 func TestGenerateCommand_WriteError(t *testing.T) {
 	is := assert.New(t)
 	var stdoutBuf, rawStderrBuf bytes.Buffer
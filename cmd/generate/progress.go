@@ -0,0 +1,57 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package generate
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/sixafter/nanoid-cli/pkg/generate"
+)
+
+// progressBarWidth is the number of cells in the progress bar of a batch.
+const progressBarWidth = 24
+
+// isTerminal reports whether w is a terminal, such as stderr in an interactive shell.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// newProgress returns a function for generate.Options.Progress that redraws the
+// progress line in place on w.
+func newProgress(w io.Writer) func(generate.Progress) {
+	return func(p generate.Progress) {
+		_, _ = fmt.Fprintf(w, "\r%s\x1b[K", progressLine(p))
+	}
+}
+
+// progressLine renders p as a single line: a bar, percentage, and estimated time
+// remaining for a batch, or the elapsed time for a stream, followed by the rate
+// and the size of the output so far.
+func progressLine(p generate.Progress) string {
+	rate := humanize.Comma(int64(p.Rate())) + " IDs/s"
+	size := humanize.Bytes(uint64(p.Bytes))
+
+	if p.Count == 0 {
+		return fmt.Sprintf("%s IDs  %s  %s  %s elapsed", humanize.Comma(int64(p.IDs)), rate, size, p.Elapsed.Round(time.Second))
+	}
+
+	done := float64(p.IDs) / float64(p.Count)
+	filled := int(done * progressBarWidth)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+
+	return fmt.Sprintf("[%s] %3.0f%%  %s/%s IDs  %s  %s  ETA %s", bar, 100*done,
+		humanize.Comma(int64(p.IDs)), humanize.Comma(int64(p.Count)), rate, size, p.Remaining().Round(time.Second))
+}
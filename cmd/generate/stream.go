@@ -0,0 +1,25 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package generate
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyPipe subscribes to SIGPIPE, which makes writes to a closed pipe fail with
+// EPIPE instead of terminating the process, so a stream piped into a consumer that
+// exits early (such as head) can shut down cleanly. SIGINT and SIGTERM are handled
+// by the root command. The returned function releases SIGPIPE.
+func notifyPipe() func() {
+	pipe := make(chan os.Signal, 1)
+	signal.Notify(pipe, syscall.SIGPIPE)
+
+	return func() {
+		signal.Stop(pipe)
+	}
+}
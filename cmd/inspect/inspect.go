@@ -0,0 +1,263 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package inspect
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sixafter/nanoid-cli/cmd/config"
+	"github.com/sixafter/nanoid-cli/internal/alphabet"
+	"github.com/sixafter/nanoid-cli/internal/checksum"
+	"github.com/sixafter/nanoid-cli/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+// Supported report formats.
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// timeLayout is RFC 3339 with the millisecond precision of sortable IDs.
+const timeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// labelWidth is the width, including dot padding, of the labels in the text report.
+const labelWidth = 20
+
+var (
+	// alphabetChars is the alphabet the inspected IDs were generated with; empty infers it.
+	alphabetChars string
+
+	// preset names a built-in alphabet or an alphabet expression used instead of alphabetChars.
+	preset string
+
+	// prefix is expected, and stripped, at the start of every inspected ID.
+	prefix string
+
+	// registryPath is a YAML type registry used to recognize IDs by their type prefix.
+	registryPath string
+
+	// sortableIDs requires every inspected ID to start with a sortable timestamp.
+	sortableIDs bool
+
+	// checksumScheme names the check characters every inspected ID is expected to end with.
+	checksumScheme string
+
+	// format selects how the report is written: text or json.
+	format string
+)
+
+// NewInspectCommand creates and returns the inspect command
+func NewInspectCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "inspect [id...]",
+		Short: "Describe existing IDs",
+		Long: `Describe one or more existing IDs: their length in characters and UTF-8 bytes,
+the character classes they use, the smallest built-in preset that covers them,
+their estimated entropy, whether they are valid default Nano IDs, and any prefix,
+sortable timestamp, or checksum they carry.
+
+IDs are read from the command line arguments, or from stdin, one per line, when
+none are given. Sortable IDs over the default alphabet start with "-", so pass
+them after "--":
+
+  nanoid inspect -- -5WFqkbRV-06w4sxb7DwP
+
+Without further flags the structure is inferred: a lowercase prefix such as
+"cus_", a timestamp that decodes to a plausible time, and a "weighted" checksum
+are recognized, and the alphabet is taken to be the smallest covering preset.
+--alphabet, --preset, --prefix, --registry, --sortable, and --checksum state the
+structure instead, and the command exits with a non-zero status when an ID does
+not have it.`,
+		RunE: runInspect, // Use RunE to handle errors gracefully
+	}
+
+	// Define flags for the inspect command
+	cmd.Flags().StringVarP(&alphabetChars, "alphabet", "a", "", "Alphabet the IDs were generated with (default: inferred)")
+	cmd.Flags().StringVar(&preset, "preset", "", "Built-in alphabet or alphabet expression the IDs were generated with")
+	cmd.Flags().StringVar(&prefix, "prefix", "", "Prefix every ID is expected to start with")
+	cmd.Flags().StringVar(&registryPath, "registry", "", "YAML type registry used to recognize IDs by their type prefix")
+	cmd.Flags().BoolVar(&sortableIDs, "sortable", false, "Expect every ID to start with a sortable timestamp")
+	cmd.Flags().StringVar(&checksumScheme, "checksum", "", "Check characters every ID is expected to end with: "+strings.Join(checksum.Names, ", "))
+	cmd.Flags().StringVarP(&format, "format", "f", formatText, "Report format: text, json")
+	config.MarkFlagValues(cmd, "format", formatText, formatJSON)
+	cmd.MarkFlagsMutuallyExclusive("alphabet", "preset")
+	cmd.MarkFlagsMutuallyExclusive("registry", "alphabet", "preset")
+	cmd.MarkFlagsMutuallyExclusive("registry", "prefix")
+	cmd.MarkFlagsMutuallyExclusive("registry", "checksum")
+
+	return cmd
+}
+
+// runInspect is the main execution function for the inspect command
+func runInspect(cmd *cobra.Command, args []string) error {
+	if format != formatText && format != formatJSON {
+		return fmt.Errorf("--format must be one of: %s, %s", formatText, formatJSON)
+	}
+
+	chars := alphabetChars
+	if preset != "" {
+		expanded, err := alphabet.Expand(preset)
+		if err != nil {
+			return fmt.Errorf("invalid --preset: %w", err)
+		}
+		chars = expanded
+	}
+	if chars != "" {
+		if _, err := alphabet.NewValidator(chars, 1); err != nil {
+			return fmt.Errorf("invalid --alphabet: %w", err)
+		}
+	}
+
+	var reg *registry.Registry
+	if registryPath != "" {
+		var err error
+		if reg, err = registry.Load(registryPath); err != nil {
+			return fmt.Errorf("invalid --registry: %w", err)
+		}
+	}
+
+	if checksumScheme != "" {
+		if _, err := checksum.New(checksumScheme, "01"); err != nil {
+			return fmt.Errorf("invalid --checksum: %w", err)
+		}
+	}
+
+	in, err := newInspector(chars, reg, prefix, sortableIDs, checksumScheme)
+	if err != nil {
+		return err
+	}
+
+	// From here on, failures are about the IDs rather than the invocation.
+	cmd.SilenceUsage = true
+
+	ids := args
+	if len(ids) == 0 {
+		if ids, err = readLines(cmd.InOrStdin()); err != nil {
+			return err
+		}
+	}
+
+	reports := make([]report, 0, len(ids))
+	failed := 0
+	for _, id := range ids {
+		r := in.inspect(id)
+		if len(r.Errors) > 0 {
+			failed++
+		}
+		reports = append(reports, r)
+	}
+
+	writer := bufio.NewWriter(cmd.OutOrStdout())
+	defer func() {
+		if err := writer.Flush(); err != nil {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error flushing writer: %v\n", err)
+		}
+	}()
+
+	if format == formatJSON {
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			return err
+		}
+	} else {
+		for i, r := range reports {
+			if i > 0 {
+				_, _ = fmt.Fprintln(writer)
+			}
+			writeText(writer, r)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d IDs do not have the expected structure", failed, len(reports))
+	}
+
+	return nil
+}
+
+// writeText writes r as aligned label: value lines.
+func writeText(w io.Writer, r report) {
+	line := func(name, value string, args ...any) {
+		_, _ = fmt.Fprintf(w, "%s: %s\n", label(name), fmt.Sprintf(value, args...))
+	}
+
+	line("ID", "%s", r.ID)
+	line("Length", "%d characters, %d bytes", r.Length, r.Bytes)
+	line("Character classes", "%s", strings.Join(r.Classes, ", "))
+	if r.Preset != "" {
+		line("Smallest preset", "%s", r.Preset)
+	} else {
+		line("Smallest preset", "none")
+	}
+	line("Estimated entropy", "%.2f bits (%d-character alphabet)", r.Entropy, r.AlphabetSize)
+	if r.ValidDefault {
+		line("Default Nano ID", "valid")
+	} else {
+		line("Default Nano ID", "invalid: %s", r.DefaultError)
+	}
+	if r.Type != "" {
+		line("Type", "%s", r.Type)
+	}
+	if r.Prefix != nil {
+		line("Prefix", "%q%s", r.Prefix.Text, inferred(r.Prefix.Guessed))
+	}
+	if r.Timestamp != nil {
+		line("Timestamp", "%s (sequence %d, %s)", r.Timestamp.Time.Format(timeLayout), r.Timestamp.Sequence, characters(r.Timestamp.Characters))
+	}
+	if r.Checksum != nil {
+		status := "verified"
+		if !r.Checksum.Verified {
+			status = "mismatch"
+		}
+		line("Checksum", "%s, %s (%s)%s", r.Checksum.Scheme, status, characters(r.Checksum.Characters), inferred(r.Checksum.Guessed))
+	}
+	for _, err := range r.Errors {
+		line("Error", "%s", err)
+	}
+}
+
+// characters returns n followed by "character" or "characters".
+func characters(n int) string {
+	if n == 1 {
+		return "1 character"
+	}
+	return fmt.Sprintf("%d characters", n)
+}
+
+// inferred returns the note appended to structure that was recognized rather than given.
+func inferred(guessed bool) string {
+	if guessed {
+		return ", inferred"
+	}
+	return ""
+}
+
+// label pads name with dots so that it lines up with the other labels in the report.
+func label(name string) string {
+	if len(name) >= labelWidth {
+		return name
+	}
+	return name + strings.Repeat(".", labelWidth-len(name))
+}
+
+// readLines returns the non-blank lines of r.
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package inspect
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sixafter/nanoid"
+	"github.com/sixafter/nanoid-cli/internal/checksum"
+	"github.com/sixafter/nanoid-cli/internal/entropy"
+	"github.com/sixafter/nanoid-cli/internal/sortable"
+	"github.com/stretchr/testify/assert"
+)
+
+// inspectJSON runs the inspect command with args and decodes its JSON report.
+func inspectJSON(t *testing.T, args ...string) ([]report, error) {
+	t.Helper()
+
+	cmd := NewInspectCommand()
+	cmd.SetArgs(append([]string{"--format", "json"}, args...))
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err := cmd.Execute()
+
+	var reports []report
+	assert.NoError(t, json.Unmarshal(outBuf.Bytes(), &reports), outBuf.String())
+	return reports, err
+}
+
+func TestInspectCommand_Text(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewInspectCommand()
+	cmd.SetArgs([]string{"V1StGXR8_Z5jdHi6B-myT"})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	err := cmd.Execute()
+	is.NoError(err)
+	is.Equal(`ID..................: V1StGXR8_Z5jdHi6B-myT
+Length..............: 21 characters, 21 bytes
+Character classes...: digits, lowercase, uppercase, symbols
+Smallest preset.....: url-safe
+Estimated entropy...: 126.00 bits (64-character alphabet)
+Default Nano ID.....: valid
+`, outBuf.String())
+}
+
+func TestInspectCommand_Describe(t *testing.T) {
+	is := assert.New(t)
+
+	reports, err := inspectJSON(t, "0123456789", "deadbeef", "αβγ", "cus_4f9Kq2mWxR9tHcA")
+	is.NoError(err)
+	is.Len(reports, 4)
+
+	digits := reports[0]
+	is.Equal([]string{"digits"}, digits.Classes)
+	is.Equal("numeric", digits.Preset)
+	is.InDelta(33.22, digits.Entropy, 0.01)
+	is.False(digits.ValidDefault)
+	is.Contains(digits.DefaultError, "id length mismatch")
+
+	hex := reports[1]
+	is.Equal([]string{"lowercase"}, hex.Classes)
+	is.Equal("hex-lower", hex.Preset)
+	is.Equal(16, hex.AlphabetSize)
+
+	greek := reports[2]
+	is.Equal(3, greek.Length)
+	is.Equal(6, greek.Bytes)
+	is.Equal([]string{"non-ascii"}, greek.Classes)
+	is.Empty(greek.Preset)
+	is.Equal(3, greek.AlphabetSize)
+
+	prefixed := reports[3]
+	is.Equal(&prefixReport{Text: "cus_", Guessed: true}, prefixed.Prefix)
+	is.Equal("base58", prefixed.Preset)
+	is.InDelta(entropy.Bits(58, 15), prefixed.Entropy, 0.01, "Expected the prefix to be excluded")
+}
+
+func TestInspectCommand_Structure(t *testing.T) {
+	is := assert.New(t)
+
+	enc, err := sortable.NewEncoding(nanoid.DefaultAlphabet)
+	is.NoError(err)
+	sum, err := checksum.New(checksum.Weighted, nanoid.DefaultAlphabet)
+	is.NoError(err)
+
+	issued := time.Now().UTC().Truncate(time.Millisecond)
+	id, err := sum.Append(enc.Encode(issued.UnixMilli(), 7) + "V1StGXR8_Z")
+	is.NoError(err)
+
+	// Sortable IDs over the default alphabet start with "-"
+	reports, err := inspectJSON(t, "--", id)
+	is.NoError(err)
+	is.Len(reports, 1)
+
+	r := reports[0]
+	is.NotNil(r.Timestamp)
+	is.True(issued.Equal(r.Timestamp.Time))
+	is.Equal(uint16(7), r.Timestamp.Sequence)
+	is.Equal(enc.Width(), r.Timestamp.Characters)
+	is.Equal(&checksumReport{Scheme: checksum.Weighted, Verified: true, Characters: 2, Guessed: true}, r.Checksum)
+	is.Equal(64, r.AlphabetSize)
+	is.InDelta(60.0, r.Entropy, 0.01, "Expected only the 10 random characters to count")
+}
+
+func TestInspectCommand_Expected(t *testing.T) {
+	is := assert.New(t)
+
+	reports, err := inspectJSON(t, "--preset", "numeric", "--prefix", "crd_", "--checksum", "luhn", "crd_79927398713", "crd_79927398714", "79927398713")
+	is.ErrorContains(err, "2 of 3 IDs do not have the expected structure")
+	is.Len(reports, 3)
+
+	is.Equal(&checksumReport{Scheme: checksum.Luhn, Verified: true, Characters: 1}, reports[0].Checksum)
+	is.Empty(reports[0].Errors)
+	is.InDelta(33.22, reports[0].Entropy, 0.01)
+
+	is.False(reports[1].Checksum.Verified)
+	is.Equal([]string{"luhn checksum: checksum mismatch"}, reports[1].Errors)
+
+	is.Nil(reports[2].Prefix)
+	is.Contains(reports[2].Errors, `does not start with the prefix "crd_"`)
+
+	reports, err = inspectJSON(t, "--preset", "numeric", "--sortable", "12345")
+	is.Error(err)
+	is.Contains(reports[0].Errors[0], "id is shorter than the 20-character lead")
+}
+
+func TestInspectCommand_Registry(t *testing.T) {
+	is := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "types.yaml")
+	is.NoError(os.WriteFile(path, []byte("types:\n  card:\n    prefix: crd\n    preset: numeric\n    length: 10\n    checksum: luhn\n"), 0o600))
+
+	reports, err := inspectJSON(t, "--registry", path, "crd_79927398713", "inv_1")
+	is.ErrorContains(err, "1 of 2 IDs do not have the expected structure")
+
+	is.Equal("card", reports[0].Type)
+	is.Equal(&prefixReport{Text: "crd_"}, reports[0].Prefix)
+	is.True(reports[0].Checksum.Verified)
+
+	is.Contains(reports[1].Errors, "id does not start with a registered type prefix")
+}
+
+func TestInspectCommand_Stdin(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewInspectCommand()
+	cmd.SetArgs([]string{"--format", "json"})
+	cmd.SetIn(strings.NewReader("abc\n\n-5WFqkbRV-06w4sxb7DwP\r\n"))
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	is.NoError(cmd.Execute())
+
+	var reports []report
+	is.NoError(json.Unmarshal(outBuf.Bytes(), &reports))
+	is.Len(reports, 2)
+	is.Equal("-5WFqkbRV-06w4sxb7DwP", reports[1].ID)
+	is.NotNil(reports[1].Timestamp)
+}
+
+func TestInspectCommand_InvalidFlags(t *testing.T) {
+	is := assert.New(t)
+
+	for _, args := range [][]string{
+		{"--alphabet", "a", "abc"},
+		{"--preset", "z-a", "abc"},
+		{"--preset", "numeric", "--alphabet", "abc", "abc"},
+		{"--checksum", "crc", "abc"},
+		{"--format", "xml", "abc"},
+		{"--registry", filepath.Join(t.TempDir(), "missing.yaml"), "abc"},
+		{"--registry", "types.yaml", "--prefix", "x_", "abc"},
+	} {
+		cmd := NewInspectCommand()
+		cmd.SetArgs(args)
+
+		var outBuf bytes.Buffer
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&outBuf)
+
+		is.Error(cmd.Execute(), strings.Join(args, " "))
+	}
+}
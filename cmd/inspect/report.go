@@ -0,0 +1,347 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package inspect
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sixafter/nanoid"
+	"github.com/sixafter/nanoid-cli/internal/alphabet"
+	"github.com/sixafter/nanoid-cli/internal/checksum"
+	"github.com/sixafter/nanoid-cli/internal/entropy"
+	"github.com/sixafter/nanoid-cli/internal/registry"
+	"github.com/sixafter/nanoid-cli/internal/sortable"
+)
+
+// Character classes reported for an ID.
+const (
+	classDigits    = "digits"
+	classLowercase = "lowercase"
+	classUppercase = "uppercase"
+	classSymbols   = "symbols"
+	classNonASCII  = "non-ascii"
+)
+
+// earliestTimestamp bounds the timestamps accepted when a sortable lead is detected
+// rather than requested: random characters almost never decode to a recent time.
+var earliestTimestamp = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// report describes one inspected ID.
+type report struct {
+	ID string `json:"id"`
+
+	// Length is the number of characters (runes) in the ID, and Bytes its UTF-8 size.
+	Length int `json:"length"`
+	Bytes  int `json:"bytes"`
+
+	// Classes lists the character classes present in the ID.
+	Classes []string `json:"character_classes"`
+
+	// Preset is the smallest built-in preset covering the body, if any.
+	Preset string `json:"smallest_preset,omitempty"`
+
+	// AlphabetSize is the size of the alphabet the entropy estimate assumes.
+	AlphabetSize int `json:"alphabet_size"`
+
+	// Entropy estimates the bits of randomness in the body, excluding any
+	// prefix, timestamp, and check characters.
+	Entropy float64 `json:"estimated_entropy_bits"`
+
+	// ValidDefault reports whether the ID is a valid default Nano ID; DefaultError says why not.
+	ValidDefault bool   `json:"valid_default"`
+	DefaultError string `json:"default_error,omitempty"`
+
+	Type      string           `json:"type,omitempty"`
+	Prefix    *prefixReport    `json:"prefix,omitempty"`
+	Timestamp *timestampReport `json:"timestamp,omitempty"`
+	Checksum  *checksumReport  `json:"checksum,omitempty"`
+
+	// Errors lists the structure the ID was expected to have but does not.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// prefixReport describes the prefix found at the start of an ID.
+type prefixReport struct {
+	Text string `json:"text"`
+
+	// Guessed is set when the prefix was recognized by its shape rather than given.
+	Guessed bool `json:"guessed,omitempty"`
+}
+
+// timestampReport describes the sortable lead of an ID.
+type timestampReport struct {
+	Time       time.Time `json:"time"`
+	Sequence   uint16    `json:"sequence"`
+	Characters int       `json:"characters"`
+}
+
+// checksumReport describes the check characters at the end of an ID.
+type checksumReport struct {
+	Scheme     string `json:"scheme"`
+	Verified   bool   `json:"verified"`
+	Characters int    `json:"characters"`
+
+	// Guessed is set when the scheme was detected rather than given; random IDs
+	// match by chance with probability 1/N² for an alphabet of N characters.
+	Guessed bool `json:"guessed,omitempty"`
+}
+
+// inspector describes IDs, using whatever structure the user told it to expect.
+type inspector struct {
+	// alphabet is the alphabet the IDs were generated with, or "" to infer it.
+	alphabet string
+
+	// registry, when set, identifies IDs by their type prefix.
+	registry *registry.Registry
+
+	// prefix is expected before every ID when set.
+	prefix string
+
+	// sortable requires every ID to start with a timestamp lead.
+	sortable bool
+
+	// checksum names the scheme every ID is expected to end with, or "" to detect it.
+	checksum string
+
+	defaultValidator *alphabet.Validator
+}
+
+// newInspector returns an inspector for the given expectations.
+func newInspector(chars string, reg *registry.Registry, prefix string, sortableIDs bool, scheme string) (*inspector, error) {
+	v, err := alphabet.NewValidator(nanoid.DefaultAlphabet, nanoid.DefaultLength)
+	if err != nil {
+		return nil, err
+	}
+	return &inspector{alphabet: chars, registry: reg, prefix: prefix, sortable: sortableIDs, checksum: scheme, defaultValidator: v}, nil
+}
+
+// inspect describes id.
+func (in *inspector) inspect(id string) report {
+	r := report{
+		ID:      id,
+		Length:  utf8.RuneCountInString(id),
+		Bytes:   len(id),
+		Classes: classes(id),
+	}
+
+	if err := in.defaultValidator.Validate(id); err != nil {
+		r.DefaultError = err.Error()
+	} else {
+		r.ValidDefault = true
+	}
+
+	// Strip the prefix, which tells us the type's alphabet and checksum when a registry is used.
+	body, chars, scheme := id, in.alphabet, in.checksum
+	switch {
+	case in.registry != nil:
+		t, rest, err := in.registry.Match(id)
+		if err != nil {
+			r.Errors = append(r.Errors, err.Error())
+			break
+		}
+		r.Type, r.Prefix = t.Name, &prefixReport{Text: t.Lead()}
+		body, chars, scheme = rest, t.Alphabet, t.Checksum
+	case in.prefix != "":
+		rest, ok := strings.CutPrefix(id, in.prefix)
+		if !ok {
+			r.Errors = append(r.Errors, fmt.Sprintf("does not start with the prefix %q", in.prefix))
+			break
+		}
+		r.Prefix = &prefixReport{Text: in.prefix}
+		body = rest
+	default:
+		if lead, ok := guessPrefix(id); ok {
+			r.Prefix = &prefixReport{Text: lead, Guessed: true}
+			body = strings.TrimPrefix(id, lead)
+		}
+	}
+
+	// Without a known alphabet, every preset covering the body is a candidate, smallest first.
+	presets := coveringPresets(body)
+	if len(presets) > 0 {
+		r.Preset = presets[0].Name
+	}
+	candidates := []string{chars}
+	if chars == "" {
+		candidates = candidates[:0]
+		for _, p := range presets {
+			candidates = append(candidates, p.Chars)
+		}
+		if len(candidates) == 0 {
+			// No preset covers the body; assume the characters it actually uses.
+			candidates = append(candidates, distinct(body))
+		}
+	}
+
+	// The alphabet a timestamp or checksum was decoded with is the one the entropy estimate uses.
+	chars = candidates[0]
+	random := utf8.RuneCountInString(body)
+	if ts, c, err := in.timestamp(body, candidates); err != nil {
+		r.Errors = append(r.Errors, err.Error())
+	} else if ts != nil {
+		r.Timestamp, chars = ts, c
+		random -= ts.Characters
+	}
+
+	cs, c, err := in.verifyChecksum(body, candidates, scheme)
+	if err != nil {
+		r.Errors = append(r.Errors, err.Error())
+	}
+	if cs != nil {
+		r.Checksum = cs
+		if cs.Verified {
+			chars = c
+		}
+		random -= cs.Characters
+	}
+
+	r.AlphabetSize = utf8.RuneCountInString(chars)
+	if r.AlphabetSize >= 2 && random > 0 {
+		r.Entropy = entropy.Bits(r.AlphabetSize, random)
+	}
+	return r
+}
+
+// timestamp decodes the sortable lead of body with the first candidate alphabet
+// that yields one, and returns that alphabet. When a lead is not required, it is
+// reported only if it decodes to a plausible time.
+func (in *inspector) timestamp(body string, candidates []string) (*timestampReport, string, error) {
+	latest := time.Now().Add(24 * time.Hour)
+	var firstErr error
+	for _, c := range candidates {
+		enc, err := sortable.NewEncoding(c)
+		if err != nil {
+			firstErr = cmp.Or(firstErr, err)
+			continue
+		}
+		stamp, err := enc.Decode(body)
+		if err != nil {
+			firstErr = cmp.Or(firstErr, err)
+			continue
+		}
+		if in.sortable || (!stamp.Time.Before(earliestTimestamp) && stamp.Time.Before(latest)) {
+			return &timestampReport{Time: stamp.Time, Sequence: stamp.Sequence, Characters: enc.Width()}, c, nil
+		}
+	}
+
+	if in.sortable {
+		return nil, "", firstErr
+	}
+	return nil, "", nil
+}
+
+// verifyChecksum checks the check characters of body against each candidate
+// alphabet and returns the one they verify with. Without an expected scheme,
+// only the two-character weighted scheme is detected, since one check character
+// matches random IDs far too often to be meaningful.
+func (in *inspector) verifyChecksum(body string, candidates []string, scheme string) (*checksumReport, string, error) {
+	guessed := scheme == ""
+	if guessed {
+		scheme = checksum.Weighted
+	}
+
+	var firstErr error
+	size := 0
+	for _, c := range candidates {
+		sum, err := checksum.New(scheme, c)
+		if err != nil {
+			firstErr = cmp.Or(firstErr, err)
+			continue
+		}
+		size = sum.Size()
+		if _, err := sum.Verify(body); err != nil {
+			firstErr = cmp.Or(firstErr, err)
+			continue
+		}
+		return &checksumReport{Scheme: scheme, Verified: true, Characters: size, Guessed: guessed}, c, nil
+	}
+
+	if guessed {
+		return nil, "", nil
+	}
+	return &checksumReport{Scheme: scheme, Characters: size}, "", fmt.Errorf("%s checksum: %w", scheme, firstErr)
+}
+
+// classes returns the character classes present in id, in a fixed order.
+func classes(id string) []string {
+	present := make(map[string]bool)
+	for _, r := range id {
+		switch {
+		case r > unicode.MaxASCII:
+			present[classNonASCII] = true
+		case '0' <= r && r <= '9':
+			present[classDigits] = true
+		case 'a' <= r && r <= 'z':
+			present[classLowercase] = true
+		case 'A' <= r && r <= 'Z':
+			present[classUppercase] = true
+		default:
+			present[classSymbols] = true
+		}
+	}
+
+	out := []string{}
+	for _, c := range []string{classDigits, classLowercase, classUppercase, classSymbols, classNonASCII} {
+		if present[c] {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// coveringPresets returns the built-in presets containing every character of s,
+// smallest first.
+func coveringPresets(s string) []alphabet.Preset {
+	if s == "" {
+		return nil
+	}
+
+	var out []alphabet.Preset
+	for _, p := range alphabet.Presets() {
+		if strings.Trim(s, p.Chars) == "" {
+			out = append(out, p)
+		}
+	}
+	slices.SortStableFunc(out, func(a, b alphabet.Preset) int {
+		return utf8.RuneCountInString(a.Chars) - utf8.RuneCountInString(b.Chars)
+	})
+	return out
+}
+
+// guessPrefix recognizes a type prefix such as "cus_": two to eight lowercase
+// letters and an underscore, followed by a body without underscores or hyphens.
+func guessPrefix(id string) (string, bool) {
+	i := strings.IndexByte(id, '_')
+	if i < 2 || i > 8 || i == len(id)-1 {
+		return "", false
+	}
+	for _, r := range id[:i] {
+		if r < 'a' || r > 'z' {
+			return "", false
+		}
+	}
+	if strings.ContainsAny(id[i+1:], "_-") {
+		return "", false
+	}
+	return id[:i+1], true
+}
+
+// distinct returns the distinct characters of s in order of first appearance.
+func distinct(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if !strings.ContainsRune(s[:i], r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
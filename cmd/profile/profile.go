@@ -0,0 +1,296 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package profile
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sixafter/nanoid-cli/cmd/config"
+	"github.com/sixafter/nanoid-cli/internal/profile"
+	"github.com/sixafter/nanoid-cli/pkg/generate"
+	"github.com/spf13/cobra"
+)
+
+// Supported output formats.
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// result is the outcome of validating one profile.
+type result struct {
+	Name  string `json:"name"`
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// NewProfileCommand creates and returns the profile command
+func NewProfileCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "profile",
+		Short: "Inspect the ID profiles defined in the configuration file",
+		Long: `Inspect the named ID profiles applied by "nanoid generate --profile".
+
+Profiles are defined in the profiles section of the configuration file, and each
+sets any of the alphabet (or preset), id-length, prefix, source, and format of
+the IDs it generates:
+
+  profiles:
+    share-link:
+      description: Short links shared in chat
+      preset: no-lookalikes
+      id-length: 10
+    api-token:
+      prefix: tok_
+      id-length: 32
+      source: ctr-drbg`,
+	}
+
+	cmd.AddCommand(newListCommand(), newShowCommand(), newValidateCommand())
+
+	return cmd
+}
+
+// newListCommand creates and returns the profile list command
+func newListCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the defined profiles",
+		Args:  cobra.NoArgs,
+	}
+
+	// format selects how profiles are written: text or json.
+	var format string
+	cmd.RunE = func(cmd *cobra.Command, args []string) error { // Use RunE to handle errors gracefully
+		return runList(cmd, args, format)
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", formatText, "Output format: text or json")
+	config.MarkFlagValues(cmd, "format", formatText, formatJSON)
+
+	return cmd
+}
+
+// newShowCommand creates and returns the profile show command
+func newShowCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show the settings of a profile",
+		Args:  cobra.ExactArgs(1),
+	}
+
+	// format selects how the profile is written: text or json.
+	var format string
+	cmd.RunE = func(cmd *cobra.Command, args []string) error { // Use RunE to handle errors gracefully
+		return runShow(cmd, args, format)
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", formatText, "Output format: text or json")
+	config.MarkFlagValues(cmd, "format", formatText, formatJSON)
+
+	return cmd
+}
+
+// newValidateCommand creates and returns the profile validate command
+func newValidateCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "validate [name...]",
+		Short: "Check that profiles generate valid IDs",
+		Long: `Check every profile, or the named ones, by building the generator each describes,
+so that errors such as duplicate alphabet characters or an unavailable random
+source are reported before the profile is first used. Exits non-zero if any
+profile is invalid.`,
+	}
+
+	// format selects how the results are written: text or json.
+	var format string
+	cmd.RunE = func(cmd *cobra.Command, args []string) error { // Use RunE to handle errors gracefully
+		return runValidate(cmd, args, format)
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", formatText, "Output format: text or json")
+	config.MarkFlagValues(cmd, "format", formatText, formatJSON)
+
+	return cmd
+}
+
+// load validates --format and returns the profiles defined in the configuration file.
+func load(cmd *cobra.Command, format string) (*profile.Set, error) {
+	if format != formatText && format != formatJSON {
+		return nil, fmt.Errorf("--format must be text or json")
+	}
+
+	cmd.SilenceUsage = true
+
+	cfg, err := config.Load(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Profiles(), nil
+}
+
+// runList is the main execution function for the profile list command
+func runList(cmd *cobra.Command, _ []string, format string) error {
+	profiles, err := load(cmd, format)
+	if err != nil {
+		return err
+	}
+	list := profiles.Profiles()
+	if list == nil {
+		list = []profile.Profile{}
+	}
+
+	return write(cmd, func(w io.Writer) error {
+		if format == formatJSON {
+			return encode(w, list)
+		}
+
+		width := len("NAME")
+		for _, p := range list {
+			width = max(width, len(p.Name))
+		}
+		if _, err := fmt.Fprintf(w, "%-*s  %s\n", width, "NAME", "DESCRIPTION"); err != nil {
+			return err
+		}
+		for _, p := range list {
+			line := fmt.Sprintf("%-*s  %s", width, p.Name, p.Description)
+			if _, err := fmt.Fprintln(w, strings.TrimRight(line, " ")); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// runShow is the main execution function for the profile show command
+func runShow(cmd *cobra.Command, args []string, format string) error {
+	profiles, err := load(cmd, format)
+	if err != nil {
+		return err
+	}
+	p, err := profiles.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+
+	return write(cmd, func(w io.Writer) error {
+		if format == formatJSON {
+			return encode(w, p)
+		}
+
+		if _, err := fmt.Fprintf(w, "Profile: %s\n", p.Name); err != nil {
+			return err
+		}
+		if p.Description != "" {
+			if _, err := fmt.Fprintf(w, "Description: %s\n", p.Description); err != nil {
+				return err
+			}
+		}
+
+		settings := p.Settings()
+		width := len("KEY")
+		for _, key := range profile.Keys {
+			width = max(width, len(key))
+		}
+		if _, err := fmt.Fprintf(w, "\n%-*s  %s\n", width, "KEY", "VALUE"); err != nil {
+			return err
+		}
+		for _, key := range profile.Keys {
+			if value, ok := settings[key]; ok {
+				if _, err := fmt.Fprintf(w, "%-*s  %s\n", width, key, value); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// runValidate is the main execution function for the profile validate command
+func runValidate(cmd *cobra.Command, args []string, format string) error {
+	profiles, err := load(cmd, format)
+	if err != nil {
+		return err
+	}
+
+	list := profiles.Profiles()
+	if len(args) > 0 {
+		list = list[:0:0]
+		for _, name := range args {
+			p, err := profiles.Lookup(name)
+			if err != nil {
+				return err
+			}
+			list = append(list, p)
+		}
+	}
+
+	results := make([]result, 0, len(list))
+	failed := 0
+	for _, p := range list {
+		r := result{Name: p.Name, Valid: true}
+		if err := p.Validate(generate.Formats()); err != nil {
+			r.Valid, r.Error = false, err.Error()
+			failed++
+		}
+		results = append(results, r)
+	}
+
+	err = write(cmd, func(w io.Writer) error {
+		if format == formatJSON {
+			return encode(w, results)
+		}
+
+		width := len("NAME")
+		for _, r := range results {
+			width = max(width, len(r.Name))
+		}
+		if _, err := fmt.Fprintf(w, "%-*s  %-6s  %s\n", width, "NAME", "RESULT", "ERROR"); err != nil {
+			return err
+		}
+		for _, r := range results {
+			verdict := "OK"
+			if !r.Valid {
+				verdict = "FAIL"
+			}
+			line := fmt.Sprintf("%-*s  %-6s  %s", width, r.Name, verdict, r.Error)
+			if _, err := fmt.Fprintln(w, strings.TrimRight(line, " ")); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d profiles failed validation", profile.ErrInvalidProfile, failed, len(results))
+	}
+	return nil
+}
+
+// write runs fn with a buffered writer on the command's output.
+func write(cmd *cobra.Command, fn func(io.Writer) error) error {
+	writer := bufio.NewWriter(cmd.OutOrStdout())
+	defer func() {
+		if err := writer.Flush(); err != nil {
+			_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "Error flushing writer:", err)
+		}
+	}()
+	return fn(writer)
+}
+
+// encode writes v as indented JSON.
+func encode(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package profile
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sixafter/nanoid-cli/cmd/config"
+	"github.com/sixafter/nanoid-cli/internal/profile"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+const profiles = `
+profiles:
+  share-link:
+    description: Short links shared in chat
+    preset: no-lookalikes
+    id-length: 10
+  api-token:
+    prefix: tok_
+    id-length: 32
+    source: crypto-rand
+    format: json
+`
+
+// run executes the profile command under a root command wired like the CLI's,
+// reading the configuration from data, and returns its output.
+func run(t *testing.T, data string, args ...string) (string, error) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	root := &cobra.Command{Use: "nanoid", PersistentPreRunE: config.Apply}
+	root.PersistentFlags().String(config.FlagName, "", "")
+	root.AddCommand(NewProfileCommand())
+	root.SetArgs(append([]string{"profile", "--config", path}, args...))
+
+	var outBuf bytes.Buffer
+	root.SetOut(&outBuf)
+	root.SetErr(&bytes.Buffer{})
+
+	err := root.Execute()
+	return outBuf.String(), err
+}
+
+func TestListCommand(t *testing.T) {
+	is := assert.New(t)
+
+	output, err := run(t, profiles, "list")
+	is.NoError(err)
+	is.Equal("NAME        DESCRIPTION\napi-token\nshare-link  Short links shared in chat\n", output)
+
+	output, err = run(t, profiles, "list", "--format", "json")
+	is.NoError(err)
+
+	var list []profile.Profile
+	is.NoError(json.Unmarshal([]byte(output), &list))
+	is.Len(list, 2)
+	is.Equal("tok_", list[0].Prefix)
+
+	output, err = run(t, "", "list", "--format", "json")
+	is.NoError(err)
+	is.Equal("[]\n", output)
+}
+
+func TestShowCommand(t *testing.T) {
+	is := assert.New(t)
+
+	output, err := run(t, profiles, "show", "api-token")
+	is.NoError(err)
+	is.Equal("Profile: api-token\n\nKEY        VALUE\nid-length  32\nprefix     tok_\nsource     crypto-rand\nformat     json\n", output)
+
+	_, err = run(t, profiles, "show", "missing")
+	is.ErrorIs(err, profile.ErrUnknownProfile)
+}
+
+func TestValidateCommand(t *testing.T) {
+	is := assert.New(t)
+
+	output, err := run(t, profiles, "validate")
+	is.NoError(err)
+	is.Contains(output, "api-token   OK\n")
+	is.Contains(output, "share-link  OK\n")
+
+	broken := profiles + `
+  broken:
+    alphabet: aabc
+  xml:
+    format: xml
+`
+	output, err = run(t, broken, "validate", "--format", "json")
+	is.ErrorIs(err, profile.ErrInvalidProfile)
+	is.ErrorContains(err, "2 of 4 profiles")
+
+	var results []result
+	is.NoError(json.Unmarshal([]byte(output), &results))
+	is.Equal(result{Name: "broken", Error: "generator: duplicate characters in alphabet"}, results[1])
+	is.False(results[3].Valid)
+
+	output, err = run(t, broken, "validate", "share-link")
+	is.NoError(err, "Only the named profiles are validated")
+	is.NotContains(output, "broken")
+}
+
+func TestCommands_Independent(t *testing.T) {
+	is := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	is.NoError(os.WriteFile(path, []byte(profiles), 0o600))
+
+	// list and show are built together, so a shared --format would leak from one to the other
+	root := &cobra.Command{Use: "nanoid", PersistentPreRunE: config.Apply}
+	root.PersistentFlags().String(config.FlagName, "", "")
+	root.AddCommand(NewProfileCommand())
+	root.SetErr(&bytes.Buffer{})
+
+	root.SetArgs([]string{"profile", "list", "--config", path, "--format", "json"})
+	root.SetOut(&bytes.Buffer{})
+	is.NoError(root.Execute())
+
+	var outBuf bytes.Buffer
+	root.SetArgs([]string{"profile", "show", "share-link", "--config", path})
+	root.SetOut(&outBuf)
+	is.NoError(root.Execute())
+	is.Contains(outBuf.String(), "Profile: share-link", "Expected text output from show without --format json")
+}
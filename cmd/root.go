@@ -6,8 +6,24 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sixafter/nanoid-cli/cmd/alphabets"
+	"github.com/sixafter/nanoid-cli/cmd/analyze"
+	"github.com/sixafter/nanoid-cli/cmd/bench"
+	"github.com/sixafter/nanoid-cli/cmd/collision"
+	"github.com/sixafter/nanoid-cli/cmd/config"
 	"github.com/sixafter/nanoid-cli/cmd/generate"
+	"github.com/sixafter/nanoid-cli/cmd/inspect"
+	"github.com/sixafter/nanoid-cli/cmd/profile"
+	"github.com/sixafter/nanoid-cli/cmd/selftest"
+	"github.com/sixafter/nanoid-cli/cmd/serve"
+	"github.com/sixafter/nanoid-cli/cmd/validate"
 	"github.com/sixafter/nanoid-cli/cmd/version"
+	"github.com/sixafter/nanoid-cli/internal/interrupt"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +32,65 @@ var RootCmd = &cobra.Command{
 	Use:   "nanoid",
 	Short: "A simple, fast, and concurrent CLI for generating secure, URL-friendly unique string IDs",
 	Long:  `NanoID CLI is a simple, fast, and concurrent command-line tool for generating secure, URL-friendly unique string IDs using the NanoID Go implementation.`,
+
+	// Flags not given on the command line are set from the environment and the configuration file.
+	PersistentPreRunE: persistentPreRun,
+}
+
+var (
+	// timeout bounds the runtime of the command; zero means no limit.
+	timeout time.Duration
+
+	// cancelTimeout releases the timer started for --timeout.
+	cancelTimeout context.CancelFunc = func() {}
+
+	// stopSignals releases SIGINT and SIGTERM once an interruptible command returns.
+	stopSignals context.CancelFunc = func() {}
+)
+
+func init() {
+	RootCmd.PersistentFlags().String(config.FlagName, "", "Configuration file (default $NANOID_CONFIG or $XDG_CONFIG_HOME/nanoid/config.yaml)")
+	RootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Stop the command after this long, exiting with status 124 (0 means no limit); generate, validate, analyze, bench, and serve stop cleanly, while other commands are killed outright")
+}
+
+// persistentPreRun applies the configuration to the flags not given on the command
+// line, then bounds the command's context by --timeout.
+//
+// Commands annotated with interrupt.Annotation stop at a clean boundary once their
+// context is done, so SIGINT and SIGTERM cancel it as well. Every other command
+// keeps the default signal behaviour and, since it neither checks its context nor
+// leaves files behind, is killed with status 124 once the context times out.
+func persistentPreRun(cmd *cobra.Command, args []string) error {
+	if err := config.Apply(cmd, args); err != nil {
+		return err
+	}
+	if timeout < 0 {
+		return fmt.Errorf("--timeout must not be negative")
+	}
+
+	// Derive from the root's context, which Execute sets afresh on every run.
+	ctx := cmd.Root().Context()
+	_, interruptible := cmd.Annotations[interrupt.Annotation]
+	if interruptible {
+		ctx, stopSignals = interrupt.NotifyContext(ctx)
+	}
+	ctx, cancel := interrupt.WithTimeout(ctx, timeout)
+	cancelTimeout = cancel
+	cmd.SetContext(ctx)
+
+	if !interruptible && timeout > 0 {
+		stop := context.AfterFunc(ctx, func() {
+			err := context.Cause(ctx)
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+			os.Exit(interrupt.ExitCode(err))
+		})
+		// Returning cancels the context, which must not kill the process
+		cancelTimeout = func() {
+			stop()
+			cancel()
+		}
+	}
+	return nil
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -24,6 +99,22 @@ var RootCmd = &cobra.Command{
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() error {
 	RootCmd.AddCommand(generate.NewGenerateCommand())
+	RootCmd.AddCommand(validate.NewValidateCommand())
+	RootCmd.AddCommand(collision.NewCollisionCommand())
+	RootCmd.AddCommand(selftest.NewSelfTestCommand())
+	RootCmd.AddCommand(serve.NewServeCommand())
+	RootCmd.AddCommand(inspect.NewInspectCommand())
+	RootCmd.AddCommand(analyze.NewAnalyzeCommand())
+	RootCmd.AddCommand(bench.NewBenchCommand())
+	RootCmd.AddCommand(alphabets.NewAlphabetsCommand())
+	RootCmd.AddCommand(config.NewConfigCommand())
+	RootCmd.AddCommand(profile.NewProfileCommand())
 	RootCmd.AddCommand(version.NewVersionCommand())
-	return RootCmd.Execute()
+
+	// Interruptible commands stop at a clean boundary on SIGINT, SIGTERM, or
+	// --timeout, and report why they stopped.
+	defer func() { stopSignals() }()
+	defer func() { cancelTimeout() }()
+
+	return RootCmd.ExecuteContext(context.Background())
 }
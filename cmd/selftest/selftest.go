@@ -0,0 +1,99 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package selftest
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sixafter/nanoid-cli/cmd/config"
+	"github.com/sixafter/nanoid-cli/internal/selftest"
+	"github.com/spf13/cobra"
+)
+
+// Supported report formats.
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// ErrSelfTestFailed is returned when at least one self-test check fails.
+var ErrSelfTestFailed = errors.New("self-test failed")
+
+// format selects how the report is written: text or json.
+var format string
+
+// NewSelfTestCommand creates and returns the selftest command
+func NewSelfTestCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "selftest",
+		Short: "Run known-answer and health checks on the random sources",
+		Long: `Run known-answer and health checks on the random sources used for Nano ID generation.
+
+The command runs the NIST SP 800-38A AES-CTR known-answer tests of the AES-CTR-DRBG,
+an RFC 8439 ChaCha20 known-answer test, and a continuous-health sanity check of
+every random source, then prints a pass/fail report. It exits with a non-zero
+status if any check fails, so it can gate ID issuance at boot.`,
+		RunE: runSelfTest, // Use RunE to handle errors gracefully
+	}
+
+	// Define flags for the selftest command
+	cmd.Flags().StringVarP(&format, "format", "f", formatText, "Report format: text, json")
+	config.MarkFlagValues(cmd, "format", formatText, formatJSON)
+
+	return cmd
+}
+
+// runSelfTest is the main execution function for the selftest command
+func runSelfTest(cmd *cobra.Command, _ []string) error {
+	if format != formatText && format != formatJSON {
+		return fmt.Errorf("--format must be one of: %s, %s", formatText, formatJSON)
+	}
+
+	// From here on, a failure is a test result rather than a usage error.
+	cmd.SilenceUsage = true
+
+	report := selftest.Run()
+
+	writer := bufio.NewWriter(cmd.OutOrStdout())
+	defer func() {
+		if err := writer.Flush(); err != nil {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error flushing writer: %v\n", err)
+		}
+	}()
+
+	if format == formatJSON {
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		width := 0
+		for _, r := range report.Results {
+			width = max(width, len(r.Name))
+		}
+
+		for _, r := range report.Results {
+			_, _ = fmt.Fprintf(writer, "%-4s  %-*s  %s\n", strings.ToUpper(r.Status), width, r.Name, r.Detail)
+		}
+
+		result := "PASSED"
+		if !report.Passed {
+			result = "FAILED"
+		}
+		_, _ = fmt.Fprintf(writer, "\nSelf-test %s (FIPS 140 mode: %t)\n", result, report.FIPS140)
+	}
+
+	if !report.Passed {
+		return ErrSelfTestFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package selftest
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/sixafter/nanoid-cli/internal/selftest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfTestCommand_Text(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewSelfTestCommand()
+	cmd.SetArgs([]string{})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	err := cmd.Execute()
+	is.NoError(err, "Expected every self-test to pass")
+
+	output := outBuf.String()
+	is.Contains(output, "PASS  aes-ctr-kat")
+	is.Contains(output, "chacha20-kat")
+	is.Contains(output, "source/ctr-drbg")
+	is.Contains(output, "Self-test PASSED")
+}
+
+func TestSelfTestCommand_JSON(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewSelfTestCommand()
+	cmd.SetArgs([]string{"--format", "json"})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	err := cmd.Execute()
+	is.NoError(err, "Expected every self-test to pass")
+
+	var report selftest.Report
+	is.NoError(json.Unmarshal(outBuf.Bytes(), &report))
+	is.True(report.Passed)
+	is.Len(report.Results, 5)
+}
+
+func TestSelfTestCommand_InvalidFormat(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewSelfTestCommand()
+	cmd.SetArgs([]string{"--format", "xml"})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&outBuf)
+
+	err := cmd.Execute()
+	is.ErrorContains(err, "--format must be one of: text, json")
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package serve
+
+import (
+	"container/list"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/sixafter/nanoid"
+	"github.com/sixafter/nanoid-cli/internal/selftest"
+)
+
+// healthInterval is how long a self-test report is reused by /healthz before the
+// self-tests run again.
+const healthInterval = 30 * time.Second
+
+// cacheKey identifies the generators that can be shared between requests.
+type cacheKey struct {
+	alphabet string
+	length   int
+}
+
+// cacheEntry is an element of the generator cache's recency list.
+type cacheEntry struct {
+	key       cacheKey
+	generator nanoid.Interface
+}
+
+// generatorCache keeps up to size generators, one per alphabet and length, and
+// evicts the least recently used one when full. Generators are safe for concurrent
+// use and pool their own buffers, so a single generator serves every request for
+// its alphabet and length.
+type generatorCache struct {
+	reader io.Reader
+	size   int
+
+	mu      sync.Mutex
+	entries map[cacheKey]*list.Element
+	order   *list.List
+}
+
+// newGeneratorCache returns a cache of at most size generators drawing from reader.
+func newGeneratorCache(reader io.Reader, size int) *generatorCache {
+	return &generatorCache{
+		reader:  reader,
+		size:    size,
+		entries: make(map[cacheKey]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the generator for alphabet and length, creating it if needed.
+func (c *generatorCache) get(alphabet string, length int) (nanoid.Interface, error) {
+	key := cacheKey{alphabet: alphabet, length: length}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		c.order.MoveToFront(e)
+		return e.Value.(*cacheEntry).generator, nil
+	}
+
+	generator, err := nanoid.NewGenerator(
+		nanoid.WithAlphabet(alphabet),
+		nanoid.WithLengthHint(uint16(length)),
+		nanoid.WithRandReader(c.reader),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, generator: generator})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+
+	return generator, nil
+}
+
+// len returns the number of cached generators.
+func (c *generatorCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// healthCache runs the self-tests at most once per interval and shares the report
+// between requests, so that probes and clients polling /healthz cannot drive
+// unbounded CPU use. Requests that arrive while the self-tests run wait for them.
+type healthCache struct {
+	run      func() selftest.Report
+	interval time.Duration
+
+	mu      sync.Mutex
+	report  selftest.Report
+	checked time.Time
+}
+
+// newHealthCache returns a cache that reuses a report from run for interval.
+func newHealthCache(run func() selftest.Report, interval time.Duration) *healthCache {
+	return &healthCache{run: run, interval: interval}
+}
+
+// get returns the latest report, running the self-tests if it is older than the interval.
+func (c *healthCache) get() selftest.Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.checked.IsZero() || time.Since(c.checked) >= c.interval {
+		c.report = c.run()
+		c.checked = time.Now()
+	}
+	return c.report
+}
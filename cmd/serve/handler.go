@@ -0,0 +1,217 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sixafter/nanoid"
+	"github.com/sixafter/nanoid-cli/cmd/version"
+	alphabets "github.com/sixafter/nanoid-cli/internal/alphabet"
+	"github.com/sixafter/nanoid-cli/internal/selftest"
+)
+
+// Supported response formats for the IDs endpoint.
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// limits bounds the work a single request may ask for.
+type limits struct {
+	// maxCount is the largest number of IDs returned by one request.
+	maxCount int
+
+	// maxLength is the longest ID a request may ask for.
+	maxLength int
+
+	// maxInFlight is the number of ID requests served concurrently; further
+	// requests are rejected with 503 Service Unavailable.
+	maxInFlight int
+}
+
+// server implements the HTTP API.
+type server struct {
+	cache  *generatorCache
+	limits limits
+
+	// inFlight holds a token for every ID request being served.
+	inFlight chan struct{}
+
+	// health runs the self-tests reported by /healthz, reusing recent reports.
+	health *healthCache
+}
+
+// idsResponse is the JSON body returned by the IDs endpoint.
+type idsResponse struct {
+	IDs []nanoid.ID `json:"ids"`
+}
+
+// versionResponse is the JSON body returned by the version endpoint.
+type versionResponse struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+}
+
+// errorResponse is the JSON body returned with every error status.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// newServer returns a server issuing IDs from the generators in cache.
+func newServer(cache *generatorCache, l limits) *server {
+	return &server{
+		cache:    cache,
+		limits:   l,
+		inFlight: make(chan struct{}, l.maxInFlight),
+		health:   newHealthCache(selftest.Run, healthInterval),
+	}
+}
+
+// handler returns the routes of the API.
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/ids", s.handleIDs)
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /version", s.handleVersion)
+	return mux
+}
+
+// handleIDs serves GET /v1/ids?count=&length=&alphabet=&preset=&format=.
+func (s *server) handleIDs(w http.ResponseWriter, r *http.Request) {
+	select {
+	case s.inFlight <- struct{}{}:
+		defer func() { <-s.inFlight }()
+	default:
+		writeJSONError(w, http.StatusServiceUnavailable, errors.New("too many concurrent requests"))
+		return
+	}
+
+	query := r.URL.Query()
+
+	count, err := intParam(query.Get("count"), 1, s.limits.maxCount)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("count: %w", err))
+		return
+	}
+
+	length, err := intParam(query.Get("length"), nanoid.DefaultLength, s.limits.maxLength)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("length: %w", err))
+		return
+	}
+
+	alphabet := nanoid.DefaultAlphabet
+	switch {
+	case query.Has("alphabet") && query.Has("preset"):
+		writeJSONError(w, http.StatusBadRequest, errors.New("alphabet and preset are mutually exclusive"))
+		return
+	case query.Has("alphabet"):
+		alphabet = query.Get("alphabet")
+	case query.Has("preset"):
+		if alphabet, err = alphabets.Expand(query.Get("preset")); err != nil {
+			writeJSONError(w, http.StatusBadRequest, fmt.Errorf("preset: %w", err))
+			return
+		}
+	}
+
+	format := query.Get("format")
+	if format == "" {
+		format = formatText
+		if strings.Contains(r.Header.Get("Accept"), "application/json") {
+			format = formatJSON
+		}
+	}
+	if format != formatText && format != formatJSON {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("format must be one of: %s, %s", formatText, formatJSON))
+		return
+	}
+
+	generator, err := s.cache.get(alphabet, length)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("alphabet: %w", err))
+		return
+	}
+
+	ids := make([]nanoid.ID, count)
+	for i := range ids {
+		if ids[i], err = generator.NewWithLength(length); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	if format == formatJSON {
+		writeJSON(w, http.StatusOK, idsResponse{IDs: ids})
+		return
+	}
+
+	var body bytes.Buffer
+	for _, id := range ids {
+		body.WriteString(string(id))
+		body.WriteByte('\n')
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(body.Bytes())
+}
+
+// handleHealth serves GET /healthz with the report of the random source self-tests,
+// which run at most once per healthInterval.
+func (s *server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	report := s.health.get()
+
+	status := http.StatusOK
+	if !report.Passed {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
+
+// handleVersion serves GET /version.
+func (s *server) handleVersion(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, versionResponse{
+		Version: version.Version(),
+		Commit:  version.GitCommitID(),
+	})
+}
+
+// intParam parses an optional positive integer query parameter, returning def when
+// it is empty and rejecting values above maxValue.
+func intParam(value string, def, maxValue int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("must be a positive integer, got %q", value)
+	}
+	if n > maxValue {
+		return 0, fmt.Errorf("must not exceed %d, got %d", maxValue, n)
+	}
+
+	return n, nil
+}
+
+// writeJSON writes v as the JSON response body with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeJSONError writes err as a JSON error response with the given status.
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
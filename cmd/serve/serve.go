@@ -0,0 +1,153 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sixafter/nanoid-cli/internal/interrupt"
+	"github.com/sixafter/nanoid-cli/internal/source"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// addr is the TCP address the server listens on.
+	addr string
+
+	// randSource names the random source shared by every generator: auto, crypto-rand, chacha20, or ctr-drbg.
+	randSource string
+
+	// maxCount is the largest number of IDs a single request may ask for.
+	maxCount int
+
+	// maxLength is the longest ID a single request may ask for.
+	maxLength int
+
+	// maxInFlight is the number of ID requests served concurrently.
+	maxInFlight int
+
+	// cacheSize is the number of distinct alphabet and length combinations whose generators are kept.
+	cacheSize int
+
+	// shutdownTimeout bounds how long in-flight requests may take to finish on shutdown.
+	shutdownTimeout time.Duration
+)
+
+// NewServeCommand creates and returns the serve command
+func NewServeCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "serve",
+		Short: "Issue Nano IDs over an HTTP API",
+		Long: `Start an HTTP server that issues Nano IDs.
+
+Endpoints:
+  GET /v1/ids    Generate IDs as text (one per line) or JSON; accepts the query
+                 parameters count, length, alphabet or preset, and format
+  GET /healthz   Report the random source self-tests, run at most every 30s
+  GET /version   Report the version and commit
+
+The IDs endpoint returns JSON when format=json is given or the Accept header asks
+for application/json. Generators are cached per alphabet and length, requests are
+bounded by --max-count, --max-length, and --max-in-flight, and the server drains
+in-flight requests for up to --shutdown-timeout on SIGINT, SIGTERM, or --timeout,
+then exits with status 130 or 124.`,
+		Annotations: map[string]string{interrupt.Annotation: "true"},
+		RunE:        runServe, // Use RunE to handle errors gracefully
+	}
+
+	// Define flags for the serve command
+	cmd.Flags().StringVar(&addr, "addr", "127.0.0.1:8080", "TCP address to listen on")
+	cmd.Flags().StringVarP(&randSource, "source", "s", source.Auto, "Random source: "+strings.Join(source.Names, ", "))
+	cmd.Flags().IntVar(&maxCount, "max-count", 1000, "Largest number of IDs returned by one request")
+	cmd.Flags().IntVar(&maxLength, "max-length", 256, "Longest ID a request may ask for")
+	cmd.Flags().IntVar(&maxInFlight, "max-in-flight", 256, "Number of ID requests served concurrently")
+	cmd.Flags().IntVar(&cacheSize, "cache-size", 64, "Number of alphabet and length combinations whose generators are cached")
+	cmd.Flags().DurationVar(&shutdownTimeout, "shutdown-timeout", 10*time.Second, "How long in-flight requests may take to finish on shutdown")
+
+	return cmd
+}
+
+// runServe is the main execution function for the serve command
+func runServe(cmd *cobra.Command, _ []string) error {
+	if maxCount <= 0 || maxLength <= 0 || maxInFlight <= 0 || cacheSize <= 0 {
+		return fmt.Errorf("--max-count, --max-length, --max-in-flight, and --cache-size must be positive integers")
+	}
+	if maxLength > math.MaxUint16 {
+		return fmt.Errorf("--max-length must be between 1 and %d, got %d", math.MaxUint16, maxLength)
+	}
+	if shutdownTimeout <= 0 {
+		return fmt.Errorf("--shutdown-timeout must be positive")
+	}
+
+	reader, sourceName, err := source.New(randSource)
+	if err != nil {
+		return fmt.Errorf("invalid --source: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	// From here on, failures are about serving rather than the invocation.
+	cmd.SilenceUsage = true
+
+	api := newServer(newGeneratorCache(reader, cacheSize), limits{
+		maxCount:    maxCount,
+		maxLength:   maxLength,
+		maxInFlight: maxInFlight,
+	})
+
+	srv := &http.Server{
+		Handler:           api.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+		MaxHeaderBytes:    1 << 16,
+	}
+
+	// The root command cancels the context on SIGINT, SIGTERM, or --timeout
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(listener)
+	}()
+
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Serving Nano IDs on http://%s (source: %s)\n", listener.Addr(), sourceName)
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "Shutting down; draining in-flight requests")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down: %w", err)
+	}
+	if err := <-served; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	// The exit status reports why the server stopped
+	return context.Cause(ctx)
+}
@@ -0,0 +1,256 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package serve
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sixafter/nanoid-cli/cmd/version"
+	"github.com/sixafter/nanoid-cli/internal/interrupt"
+	"github.com/sixafter/nanoid-cli/internal/selftest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer() *server {
+	return newServer(newGeneratorCache(rand.Reader, 4), limits{maxCount: 100, maxLength: 64, maxInFlight: 4})
+}
+
+func get(s *server, target string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	s.handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServe_IDsText(t *testing.T) {
+	is := assert.New(t)
+
+	rec := get(newTestServer(), "/v1/ids?count=3&length=10")
+	is.Equal(http.StatusOK, rec.Code)
+	is.Equal("text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	ids := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	is.Len(ids, 3)
+	for _, id := range ids {
+		is.Len(id, 10)
+	}
+}
+
+func TestServe_IDsJSON(t *testing.T) {
+	is := assert.New(t)
+
+	for _, rec := range []*httptest.ResponseRecorder{
+		get(newTestServer(), "/v1/ids?count=2&alphabet=abc&format=json"),
+		get(newTestServer(), "/v1/ids?count=2&alphabet=abc", "Accept", "application/json"),
+	} {
+		is.Equal(http.StatusOK, rec.Code)
+		is.Equal("application/json", rec.Header().Get("Content-Type"))
+
+		var body idsResponse
+		is.NoError(json.Unmarshal(rec.Body.Bytes(), &body))
+		is.Len(body.IDs, 2)
+		for _, id := range body.IDs {
+			is.Len(string(id), 21)
+			is.Empty(strings.Trim(string(id), "abc"))
+		}
+	}
+}
+
+func TestServe_IDsPreset(t *testing.T) {
+	is := assert.New(t)
+
+	rec := get(newTestServer(), "/v1/ids?count=5&preset=numeric")
+	is.Equal(http.StatusOK, rec.Code)
+	for _, id := range strings.Split(strings.TrimSpace(rec.Body.String()), "\n") {
+		is.Len(id, 21)
+		is.Empty(strings.Trim(id, "0123456789"))
+	}
+}
+
+func TestServe_IDsLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		want   string
+	}{
+		{"count too large", "/v1/ids?count=101", "count: must not exceed 100"},
+		{"count not a number", "/v1/ids?count=ten", "count: must be a positive integer"},
+		{"zero length", "/v1/ids?length=0", "length: must be a positive integer"},
+		{"length too large", "/v1/ids?length=65", "length: must not exceed 64"},
+		{"duplicate alphabet", "/v1/ids?alphabet=aab", "alphabet:"},
+		{"short alphabet", "/v1/ids?alphabet=a", "alphabet:"},
+		{"bad format", "/v1/ids?format=xml", "format must be one of"},
+		{"bad preset", "/v1/ids?preset=z-a", "preset: invalid alphabet expression"},
+		{"alphabet and preset", "/v1/ids?preset=numeric&alphabet=abc", "mutually exclusive"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			is := assert.New(t)
+
+			rec := get(newTestServer(), tc.target)
+			is.Equal(http.StatusBadRequest, rec.Code)
+
+			var body errorResponse
+			is.NoError(json.Unmarshal(rec.Body.Bytes(), &body))
+			is.Contains(body.Error, tc.want)
+		})
+	}
+}
+
+func TestServe_InFlightLimit(t *testing.T) {
+	is := assert.New(t)
+
+	s := newTestServer()
+	for range s.limits.maxInFlight {
+		s.inFlight <- struct{}{}
+	}
+
+	rec := get(s, "/v1/ids")
+	is.Equal(http.StatusServiceUnavailable, rec.Code)
+
+	<-s.inFlight
+	rec = get(s, "/v1/ids")
+	is.Equal(http.StatusOK, rec.Code)
+}
+
+func TestServe_Health(t *testing.T) {
+	is := assert.New(t)
+
+	s := newTestServer()
+	rec := get(s, "/healthz")
+	is.Equal(http.StatusOK, rec.Code)
+
+	var report selftest.Report
+	is.NoError(json.Unmarshal(rec.Body.Bytes(), &report))
+	is.True(report.Passed)
+	is.NotEmpty(report.Results)
+
+	s.health = newHealthCache(func() selftest.Report { return selftest.Report{Passed: false} }, time.Minute)
+	rec = get(s, "/healthz")
+	is.Equal(http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestHealthCache(t *testing.T) {
+	is := assert.New(t)
+
+	runs := 0
+	c := newHealthCache(func() selftest.Report {
+		runs++
+		return selftest.Report{Passed: runs == 1}
+	}, 50*time.Millisecond)
+
+	// Requests within the interval share one run of the self-tests
+	for range 10 {
+		is.True(c.get().Passed)
+	}
+	is.Equal(1, runs)
+
+	// The self-tests run again once the report is older than the interval
+	time.Sleep(60 * time.Millisecond)
+	is.False(c.get().Passed)
+	is.Equal(2, runs)
+}
+
+func TestServe_Version(t *testing.T) {
+	is := assert.New(t)
+
+	rec := get(newTestServer(), "/version")
+	is.Equal(http.StatusOK, rec.Code)
+
+	var body versionResponse
+	is.NoError(json.Unmarshal(rec.Body.Bytes(), &body))
+	is.Equal(version.Version(), body.Version)
+	is.Equal(version.GitCommitID(), body.Commit)
+}
+
+func TestServe_MethodNotAllowed(t *testing.T) {
+	is := assert.New(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/ids", nil)
+	rec := httptest.NewRecorder()
+	newTestServer().handler().ServeHTTP(rec, req)
+	is.Equal(http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestGeneratorCache(t *testing.T) {
+	is := assert.New(t)
+
+	cache := newGeneratorCache(rand.Reader, 2)
+
+	a, err := cache.get("abc", 8)
+	is.NoError(err)
+	again, err := cache.get("abc", 8)
+	is.NoError(err)
+	is.Same(a, again, "Expected the cached generator to be reused")
+
+	_, err = cache.get("abc", 9)
+	is.NoError(err)
+	_, err = cache.get("xyz", 8)
+	is.NoError(err)
+	is.Equal(2, cache.len(), "Expected the least recently used generator to be evicted")
+
+	evicted, err := cache.get("abc", 8)
+	is.NoError(err)
+	is.NotSame(a, evicted)
+
+	_, err = cache.get("aa", 8)
+	is.Error(err)
+	is.Equal(2, cache.len(), "Expected invalid alphabets not to be cached")
+}
+
+func TestServeCommand_Shutdown(t *testing.T) {
+	is := assert.New(t)
+
+	// Cancelling the context with ErrTimeout stands in for --timeout
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	time.AfterFunc(200*time.Millisecond, func() { cancel(interrupt.ErrTimeout) })
+
+	cmd := NewServeCommand()
+	cmd.SetArgs([]string{"--addr", "127.0.0.1:0"})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err := cmd.ExecuteContext(ctx)
+	is.ErrorIs(err, interrupt.ErrTimeout, "Expected a clean shutdown reporting why the server stopped")
+	is.Equal(interrupt.ExitTimeout, interrupt.ExitCode(err))
+	is.Contains(errBuf.String(), "Serving Nano IDs on http://127.0.0.1:")
+	is.Contains(errBuf.String(), "Shutting down")
+}
+
+func TestServeCommand_InvalidFlags(t *testing.T) {
+	is := assert.New(t)
+
+	for _, args := range [][]string{
+		{"--max-count", "0"},
+		{"--max-length", "65536"},
+		{"--shutdown-timeout", "0s"},
+		{"--source", "dev-random"},
+	} {
+		cmd := NewServeCommand()
+		cmd.SetArgs(append(args, "--addr", "127.0.0.1:0"))
+
+		var outBuf bytes.Buffer
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&outBuf)
+
+		is.Error(cmd.Execute(), strings.Join(args, " "))
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package validate
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sixafter/nanoid"
+	"github.com/sixafter/nanoid-cli/internal/alphabet"
+	"github.com/sixafter/nanoid-cli/internal/checksum"
+	"github.com/sixafter/nanoid-cli/internal/interrupt"
+	"github.com/sixafter/nanoid-cli/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+// ErrValidationFailed is returned under --quiet when any ID fails validation. It
+// carries nothing worth printing, so main exits with a non-zero status without
+// reporting it.
+var ErrValidationFailed = errors.New("validation failed")
+
+// maxLineSize is the largest input line, in bytes, accepted when reading IDs from a file or stdin.
+const maxLineSize = 1024 * 1024
+
+var (
+	// idLength specifies the number of characters a valid ID must contain.
+	idLength int
+
+	// alphabetChars defines the set of characters a valid ID may be composed of.
+	alphabetChars string
+
+	// preset names a built-in alphabet or an alphabet expression used instead of alphabetChars.
+	preset string
+
+	// registryPath is a YAML type registry; when set, IDs are matched to a type by prefix
+	// and their bodies are checked against that type's alphabet and length.
+	registryPath string
+
+	// checksumScheme names the scheme of the check characters a valid ID must end with, if any.
+	checksumScheme string
+
+	// file names a file to read IDs from, one per line. "-" reads from stdin.
+	file string
+
+	// quiet suppresses the per-line report so that only the exit status signals the result.
+	quiet bool
+)
+
+// NewValidateCommand creates and returns the validate command
+func NewValidateCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "validate [id...]",
+		Short: "Validate Nano IDs against an alphabet and length",
+		Long: `Validate one or more Nano IDs against an alphabet and length.
+
+IDs are read from the command line arguments, from --file, or from stdin
+when neither is given. Each ID is reported on its own line together with its
+line number, and the command exits with a non-zero status when any ID fails.
+
+If --id-length is not specified, a default length of 21 is used.
+If --alphabet is not specified, the default ASCII alphabet is used.
+--preset selects a built-in alphabet or an alphabet expression instead.
+With --registry, each ID is matched to a registered type by its prefix, and the
+rest of the ID is checked against that type's alphabet, length, and checksum.
+--checksum requires every ID to end with valid check characters of that scheme,
+following a body of --id-length characters.`,
+		Annotations: map[string]string{interrupt.Annotation: "true"},
+		RunE:        runValidate, // Use RunE to handle errors gracefully
+	}
+
+	// Define flags for the validate command
+	cmd.Flags().IntVarP(&idLength, "id-length", "l", nanoid.DefaultLength, "Length a valid Nano ID must have")
+	cmd.Flags().StringVarP(&alphabetChars, "alphabet", "a", nanoid.DefaultAlphabet, "Alphabet a valid Nano ID must be drawn from")
+	cmd.Flags().StringVar(&preset, "preset", "", "Built-in alphabet or alphabet expression a valid Nano ID must be drawn from")
+	cmd.Flags().StringVar(&registryPath, "registry", "", "YAML type registry used to validate prefixed IDs by type")
+	cmd.Flags().StringVar(&checksumScheme, "checksum", "", "Check characters a valid Nano ID must end with: "+strings.Join(checksum.Names, ", "))
+	cmd.Flags().StringVar(&file, "file", "", "Read IDs from a file, one per line (\"-\" for stdin)")
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Suppress per-line output; report the result through the exit status only")
+	cmd.MarkFlagsMutuallyExclusive("registry", "alphabet", "preset")
+	cmd.MarkFlagsMutuallyExclusive("registry", "id-length")
+	cmd.MarkFlagsMutuallyExclusive("registry", "checksum")
+
+	return cmd
+}
+
+// runValidate is the main execution function for the validate command
+func runValidate(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 && file != "" {
+		return fmt.Errorf("IDs cannot be given both as arguments and with --file")
+	}
+
+	chars := alphabetChars
+	if preset != "" {
+		expanded, err := alphabet.Expand(preset)
+		if err != nil {
+			return fmt.Errorf("invalid --preset: %w", err)
+		}
+		chars = expanded
+	}
+
+	// validate checks a single ID and returns the name of its type, if any.
+	var validate func(id string) (string, error)
+	if registryPath != "" {
+		reg, err := registry.Load(registryPath)
+		if err != nil {
+			return fmt.Errorf("invalid --registry: %w", err)
+		}
+
+		validators := make(map[string]*alphabet.Validator)
+		sums := make(map[string]*checksum.Checksum)
+		validate = func(id string) (string, error) {
+			t, body, err := reg.Match(id)
+			if err != nil {
+				return "", err
+			}
+
+			v, ok := validators[t.Name]
+			if !ok {
+				if v, err = alphabet.NewValidator(t.Alphabet, t.Length); err != nil {
+					return t.Name, err
+				}
+				validators[t.Name] = v
+				if t.Checksum != "" {
+					if sums[t.Name], err = checksum.New(t.Checksum, t.Alphabet); err != nil {
+						return t.Name, err
+					}
+				}
+			}
+			return t.Name, verify(v, sums[t.Name], body)
+		}
+	} else {
+		validator, err := alphabet.NewValidator(chars, idLength)
+		if err != nil {
+			return fmt.Errorf("invalid validation rules: %w", err)
+		}
+
+		var sum *checksum.Checksum
+		if checksumScheme != "" {
+			if sum, err = checksum.New(checksumScheme, chars); err != nil {
+				return fmt.Errorf("invalid --checksum: %w", err)
+			}
+		}
+		validate = func(id string) (string, error) {
+			return "", verify(validator, sum, id)
+		}
+	}
+
+	// From here on, failures are about the IDs rather than the invocation.
+	cmd.SilenceUsage = true
+	if quiet {
+		cmd.SilenceErrors = true
+	}
+
+	writer := bufio.NewWriter(cmd.OutOrStdout())
+	defer func() {
+		if err := writer.Flush(); err != nil {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error flushing writer: %v\n", err)
+		}
+	}()
+
+	var total, failed int
+	check := func(line int, id string) error {
+		if err := cmd.Context().Err(); err != nil {
+			return fmt.Errorf("validation stopped after %d IDs: %w", total, context.Cause(cmd.Context()))
+		}
+		total++
+		typeName, verr := validate(id)
+		if verr != nil {
+			failed++
+		}
+
+		if quiet {
+			return nil
+		}
+
+		if verr != nil {
+			_, err := fmt.Fprintf(writer, "line %d: FAIL %s: %v\n", line, id, verr)
+			return err
+		}
+		if typeName != "" {
+			_, err := fmt.Fprintf(writer, "line %d: PASS %s (%s)\n", line, id, typeName)
+			return err
+		}
+		_, err := fmt.Fprintf(writer, "line %d: PASS %s\n", line, id)
+		return err
+	}
+
+	if len(args) > 0 {
+		for i, id := range args {
+			if err := check(i+1, id); err != nil {
+				return err
+			}
+		}
+	} else {
+		var in io.Reader = cmd.InOrStdin()
+		if file != "" && file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			in = f
+		}
+
+		// Reads from a slow pipe or a terminal still stop on SIGINT
+		if err := scanLines(interrupt.Reader(cmd.Context(), in), check); err != nil {
+			if cmd.Context().Err() != nil {
+				return fmt.Errorf("validation stopped after %d IDs: %w", total, context.Cause(cmd.Context()))
+			}
+			return err
+		}
+	}
+
+	if failed > 0 {
+		if quiet {
+			return ErrValidationFailed
+		}
+		return fmt.Errorf("%d of %d IDs failed validation", failed, total)
+	}
+
+	return nil
+}
+
+// verify checks that id is a body accepted by v, followed by valid check characters
+// when sum is non-nil.
+func verify(v *alphabet.Validator, sum *checksum.Checksum, id string) error {
+	if sum == nil {
+		return v.Validate(id)
+	}
+
+	if err := v.Validate(sum.Body(id)); err != nil {
+		return err
+	}
+	_, err := sum.Verify(id)
+	return err
+}
+
+// scanLines calls fn with every non-blank line of r and its 1-based line number.
+func scanLines(r io.Reader, fn func(line int, id string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		id := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(id) == "" {
+			continue
+		}
+		if err := fn(line, id); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
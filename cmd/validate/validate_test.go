@@ -0,0 +1,255 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package validate
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sixafter/nanoid-cli/internal/interrupt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCommand_Args(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewValidateCommand()
+	cmd.SetArgs([]string{"--alphabet", "abc", "--id-length", "3", "abc", "cab"})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	err := cmd.Execute()
+	is.NoError(err, "Expected no error when all IDs are valid")
+
+	lines := strings.Split(strings.TrimSpace(outBuf.String()), "\n")
+	is.Equal([]string{"line 1: PASS abc", "line 2: PASS cab"}, lines)
+}
+
+func TestValidateCommand_Stdin(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewValidateCommand()
+	cmd.SetArgs([]string{"--alphabet", "abc", "--id-length", "3"})
+	cmd.SetIn(strings.NewReader("abc\n\nabd\r\nab\n"))
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err := cmd.Execute()
+	is.ErrorContains(err, "2 of 3 IDs failed validation")
+
+	// Blank lines are skipped but still counted for line numbers
+	output := outBuf.String()
+	is.Contains(output, "line 1: PASS abc")
+	is.Contains(output, "line 3: FAIL abd")
+	is.Contains(output, "line 4: FAIL ab: id length mismatch")
+	is.NotContains(errBuf.String(), "Usage:", "Expected usage to be suppressed for failed IDs")
+}
+
+func TestValidateCommand_Interrupted(t *testing.T) {
+	is := assert.New(t)
+
+	// A context cancelled with ErrInterrupted stands in for SIGINT
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(interrupt.ErrInterrupted)
+
+	cmd := NewValidateCommand()
+	cmd.SetArgs([]string{"--alphabet", "abc", "--id-length", "3"})
+	cmd.SetIn(strings.NewReader("abc\ncab\n"))
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err := cmd.ExecuteContext(ctx)
+	is.ErrorIs(err, interrupt.ErrInterrupted)
+	is.ErrorContains(err, "validation stopped after 0 IDs")
+	is.Empty(outBuf.String())
+}
+
+func TestValidateCommand_InterruptedRead(t *testing.T) {
+	is := assert.New(t)
+
+	// An interrupt stops a command waiting on input that never arrives, as with "sleep 10 | nanoid validate"
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	time.AfterFunc(100*time.Millisecond, func() { cancel(interrupt.ErrInterrupted) })
+
+	in, w := io.Pipe()
+	defer func() { _ = w.Close() }()
+
+	cmd := NewValidateCommand()
+	cmd.SetArgs([]string{"--alphabet", "abc", "--id-length", "3"})
+	cmd.SetIn(in)
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err := cmd.ExecuteContext(ctx)
+	is.ErrorIs(err, interrupt.ErrInterrupted)
+	is.ErrorContains(err, "validation stopped after 0 IDs")
+}
+
+func TestValidateCommand_File(t *testing.T) {
+	is := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "ids.txt")
+	is.NoError(os.WriteFile(path, []byte("ααβ\nβγα\n"), 0o600))
+
+	cmd := NewValidateCommand()
+	cmd.SetArgs([]string{"--alphabet", "αβγ", "--id-length", "3", "--file", path})
+
+	var outBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+
+	err := cmd.Execute()
+	is.NoError(err, "Expected multibyte IDs to validate against a multibyte alphabet")
+	is.Contains(outBuf.String(), "line 2: PASS βγα")
+}
+
+func TestValidateCommand_Quiet(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewValidateCommand()
+	cmd.SetArgs([]string{"--quiet", "--alphabet", "abc", "--id-length", "3", "abc", "xyz"})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err := cmd.Execute()
+	is.ErrorIs(err, ErrValidationFailed, "Expected an error when an ID fails")
+	is.Empty(outBuf.String(), "Expected no per-line output in quiet mode")
+	is.Empty(errBuf.String(), "Expected no error output in quiet mode")
+}
+
+func TestValidateCommand_Preset(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewValidateCommand()
+	cmd.SetArgs([]string{"--preset", "a-c,-b", "--id-length", "3", "aca", "abc"})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err := cmd.Execute()
+	is.ErrorContains(err, "1 of 2 IDs failed validation")
+
+	lines := strings.Split(strings.TrimSpace(outBuf.String()), "\n")
+	is.Equal("line 1: PASS aca", lines[0])
+	is.True(strings.HasPrefix(lines[1], "line 2: FAIL abc"))
+}
+
+func TestValidateCommand_Checksum(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewValidateCommand()
+	cmd.SetArgs([]string{"--alphabet", "0123456789", "--id-length", "10", "--checksum", "luhn", "79927398713", "79927398731", "7992739871"})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err := cmd.Execute()
+	is.ErrorContains(err, "2 of 3 IDs failed validation")
+
+	lines := strings.Split(strings.TrimSpace(outBuf.String()), "\n")
+	is.Len(lines, 3)
+	is.Equal("line 1: PASS 79927398713", lines[0])
+	is.Equal("line 2: FAIL 79927398731: checksum mismatch", lines[1])
+	is.Contains(lines[2], "line 3: FAIL 7992739871: id length mismatch")
+}
+
+func TestValidateCommand_RegistryChecksum(t *testing.T) {
+	is := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "types.yaml")
+	is.NoError(os.WriteFile(path, []byte("types:\n  card:\n    prefix: crd\n    preset: numeric\n    length: 10\n    checksum: luhn\n"), 0o600))
+
+	cmd := NewValidateCommand()
+	cmd.SetArgs([]string{"--registry", path, "crd_79927398713", "crd_79927398714"})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err := cmd.Execute()
+	is.ErrorContains(err, "1 of 2 IDs failed validation")
+
+	lines := strings.Split(strings.TrimSpace(outBuf.String()), "\n")
+	is.Equal("line 1: PASS crd_79927398713 (card)", lines[0])
+	is.Equal("line 2: FAIL crd_79927398714: checksum mismatch", lines[1])
+}
+
+func TestValidateCommand_Registry(t *testing.T) {
+	is := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "types.yaml")
+	is.NoError(os.WriteFile(path, []byte("types:\n  order:\n    prefix: ord\n    alphabet: abc\n    length: 4\n  user:\n    prefix: usr\n    separator: \"-\"\n    preset: numeric\n    length: 3\n"), 0o600))
+
+	cmd := NewValidateCommand()
+	cmd.SetArgs([]string{"--registry", path, "ord_abca", "usr-123", "usr-12a", "inv_abca", "ord_abc"})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err := cmd.Execute()
+	is.ErrorContains(err, "3 of 5 IDs failed validation")
+
+	lines := strings.Split(strings.TrimSpace(outBuf.String()), "\n")
+	is.Len(lines, 5)
+	is.Equal("line 1: PASS ord_abca (order)", lines[0])
+	is.Equal("line 2: PASS usr-123 (user)", lines[1])
+	is.Contains(lines[2], "line 3: FAIL usr-12a: id contains a character outside the alphabet")
+	is.Contains(lines[3], "line 4: FAIL inv_abca: id does not start with a registered type prefix")
+	is.Contains(lines[4], "line 5: FAIL ord_abc: id length mismatch")
+}
+
+func TestValidateCommand_InvalidRegistry(t *testing.T) {
+	is := assert.New(t)
+
+	for _, args := range [][]string{
+		{"--registry", filepath.Join(t.TempDir(), "missing.yaml"), "abc"},
+		{"--registry", "types.yaml", "--alphabet", "abc", "abc"},
+		{"--registry", "types.yaml", "--id-length", "3", "abc"},
+		{"--registry", "types.yaml", "--checksum", "luhn", "abc"},
+		{"--checksum", "crc", "abc"},
+	} {
+		cmd := NewValidateCommand()
+		cmd.SetArgs(args)
+
+		var outBuf bytes.Buffer
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&outBuf)
+
+		is.Error(cmd.Execute(), strings.Join(args, " "))
+	}
+}
+
+func TestValidateCommand_InvalidAlphabet(t *testing.T) {
+	is := assert.New(t)
+
+	cmd := NewValidateCommand()
+	cmd.SetArgs([]string{"--alphabet", "aab", "abc"})
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+
+	err := cmd.Execute()
+	is.ErrorContains(err, "duplicate characters in alphabet")
+}
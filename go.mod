@@ -9,20 +9,20 @@ go 1.26
 
 require (
 	github.com/dustin/go-humanize v1.0.1
+	github.com/sixafter/aes-ctr-drbg v1.19.1
 	github.com/sixafter/nanoid v1.64.3
+	github.com/sixafter/prng-chacha v1.16.3
 	github.com/sixafter/semver v1.12.0
 	github.com/spf13/cobra v1.10.2
+	github.com/spf13/pflag v1.0.10
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/crypto v0.52.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/sixafter/aes-ctr-drbg v1.19.1 // indirect
-	github.com/sixafter/prng-chacha v1.16.3 // indirect
-	github.com/spf13/pflag v1.0.10 // indirect
-	golang.org/x/crypto v0.52.0 // indirect
 	golang.org/x/sys v0.45.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,242 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package alphabet
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sixafter/nanoid"
+)
+
+var (
+	// ErrInvalidExpression is returned when an alphabet expression cannot be parsed.
+	ErrInvalidExpression = errors.New("invalid alphabet expression")
+
+	// ErrEmptyAlphabet is returned when an alphabet expression expands to no characters.
+	ErrEmptyAlphabet = errors.New("alphabet expression expands to no characters")
+
+	// ErrUnknownSet is returned when an alphabet expression names a set that does not exist.
+	ErrUnknownSet = errors.New("unknown preset or character set")
+)
+
+// Preset is a named, commonly used alphabet.
+type Preset struct {
+	// Name identifies the preset in --preset and in alphabet expressions.
+	Name string
+
+	// Description explains what the preset is for.
+	Description string
+
+	// Chars is the alphabet itself.
+	Chars string
+}
+
+// lookalikes holds the characters that are easily confused with one another in
+// common fonts. It is available in expressions, typically to subtract it.
+const lookalikes = "0125IOSZlosuv"
+
+// presets lists the built-in presets in the order they are documented.
+var presets = []Preset{
+	{Name: "numeric", Description: "Decimal digits", Chars: "0123456789"},
+	{Name: "hex-lower", Description: "Lowercase hexadecimal", Chars: "0123456789abcdef"},
+	{Name: "base32-crockford", Description: "Crockford's Base32, without I, L, O, and U", Chars: "0123456789ABCDEFGHJKMNPQRSTVWXYZ"},
+	{Name: "base58", Description: "Bitcoin Base58, without 0, O, I, and l", Chars: "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"},
+	{Name: "no-lookalikes", Description: "Alphanumerics without " + lookalikes, Chars: "346789ABCDEFGHJKLMNPQRTUVWXYabcdefghijkmnpqrtwxyz"},
+	{Name: "alphanumeric", Description: "Digits and upper- and lowercase letters", Chars: "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"},
+	{Name: "url-safe", Description: "The default Nano ID alphabet", Chars: nanoid.DefaultAlphabet},
+}
+
+// sets holds the named character sets usable in expressions that are not presets.
+var sets = map[string]string{
+	"lookalikes": lookalikes,
+}
+
+// Presets returns the built-in presets in the order they are documented.
+func Presets() []Preset {
+	return slices.Clone(presets)
+}
+
+// LookupPreset returns the preset with the given name.
+func LookupPreset(name string) (Preset, bool) {
+	for _, p := range presets {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Preset{}, false
+}
+
+// Expand resolves a preset name or an alphabet expression to an alphabet.
+//
+// An expression is a comma-separated list of terms applied from left to right:
+//
+//   - a preset name, or "lookalikes", adds that set of characters;
+//   - a range such as "a-z" adds every character from the first to the last;
+//   - any other text adds its characters literally;
+//   - a leading "-" subtracts the characters of the term instead.
+//
+// A backslash escapes the next character, so "\," and "\-" denote a literal comma
+// and hyphen. To catch misspelled names, an unescaped term of two or more
+// lowercase letters, digits, and hyphens must be a known name; escape any of its
+// characters, as in "\abc", or list them separately, as in "a,b,c", to add them
+// literally. Characters keep the order in which they were first added, and
+// repeated characters are added only once. The result is not otherwise validated;
+// nanoid.WithAlphabet applies the usual alphabet rules.
+func Expand(expr string) (string, error) {
+	if p, ok := LookupPreset(expr); ok {
+		return p.Chars, nil
+	}
+	if !utf8.ValidString(expr) {
+		return "", fmt.Errorf("%w: %w", ErrInvalidExpression, ErrInvalidUTF8)
+	}
+
+	terms, err := splitTerms(expr)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		order   []rune
+		present = make(map[rune]bool)
+	)
+	for _, t := range terms {
+		chars, err := t.expand()
+		if err != nil {
+			return "", err
+		}
+
+		for _, r := range chars {
+			if t.subtract {
+				present[r] = false
+			} else if _, seen := present[r]; !seen {
+				order = append(order, r)
+				present[r] = true
+			} else {
+				present[r] = true
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, r := range order {
+		if present[r] {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "", ErrEmptyAlphabet
+	}
+
+	return b.String(), nil
+}
+
+// term is a single comma-separated element of an alphabet expression.
+type term struct {
+	// text is the term with escapes removed.
+	text []rune
+
+	// escaped marks the characters of text that were escaped with a backslash.
+	escaped []bool
+
+	// subtract is true when the term removes characters instead of adding them.
+	subtract bool
+}
+
+// splitTerms splits an expression on unescaped commas.
+func splitTerms(expr string) ([]term, error) {
+	var (
+		terms []term
+		cur   term
+	)
+
+	runes := []rune(expr)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("%w: trailing backslash", ErrInvalidExpression)
+			}
+			i++
+			cur.text = append(cur.text, runes[i])
+			cur.escaped = append(cur.escaped, true)
+		case r == ',':
+			terms = append(terms, cur)
+			cur = term{}
+		default:
+			cur.text = append(cur.text, r)
+			cur.escaped = append(cur.escaped, false)
+		}
+	}
+	terms = append(terms, cur)
+
+	for i := range terms {
+		t := &terms[i]
+		if len(t.text) == 0 {
+			return nil, fmt.Errorf("%w: empty term in %q", ErrInvalidExpression, expr)
+		}
+		if t.text[0] == '-' && !t.escaped[0] {
+			t.subtract = true
+			t.text, t.escaped = t.text[1:], t.escaped[1:]
+			if len(t.text) == 0 {
+				return nil, fmt.Errorf("%w: nothing to subtract in %q", ErrInvalidExpression, expr)
+			}
+		}
+	}
+
+	return terms, nil
+}
+
+// expand returns the characters the term denotes.
+func (t term) expand() ([]rune, error) {
+	if !slices.Contains(t.escaped, true) {
+		name := string(t.text)
+		if p, ok := LookupPreset(name); ok {
+			return []rune(p.Chars), nil
+		}
+		if chars, ok := sets[name]; ok {
+			return []rune(chars), nil
+		}
+		if len(t.text) > 1 && looksLikeName(name) && !isRange(t) {
+			return nil, fmt.Errorf("%w: %q (escape a character, as in \\%s, to use it literally)", ErrUnknownSet, name, name)
+		}
+	}
+
+	if isRange(t) {
+		lo, hi := t.text[0], t.text[2]
+		if lo > hi {
+			return nil, fmt.Errorf("%w: range %q is reversed", ErrInvalidExpression, string(t.text))
+		}
+		if hi-lo >= nanoid.MaxAlphabetLength {
+			return nil, fmt.Errorf("%w: range %q exceeds %d characters", ErrInvalidExpression, string(t.text), nanoid.MaxAlphabetLength)
+		}
+
+		chars := make([]rune, 0, hi-lo+1)
+		for r := lo; r <= hi; r++ {
+			if utf8.ValidRune(r) {
+				chars = append(chars, r)
+			}
+		}
+		return chars, nil
+	}
+
+	return t.text, nil
+}
+
+// isRange reports whether the term has the form "x-y".
+func isRange(t term) bool {
+	return len(t.text) == 3 && t.text[1] == '-' && !t.escaped[1]
+}
+
+// looksLikeName reports whether s consists only of lowercase ASCII letters, digits,
+// and hyphens, as preset and set names do.
+func looksLikeName(s string) bool {
+	return strings.Trim(s, "abcdefghijklmnopqrstuvwxyz0123456789-") == ""
+}
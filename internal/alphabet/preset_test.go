@@ -0,0 +1,120 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package alphabet
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/sixafter/nanoid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresets(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	sizes := map[string]int{
+		"numeric":          10,
+		"hex-lower":        16,
+		"base32-crockford": 32,
+		"base58":           58,
+		"no-lookalikes":    49,
+		"alphanumeric":     62,
+		"url-safe":         64,
+	}
+
+	is.Len(Presets(), len(sizes))
+	for _, p := range Presets() {
+		is.Equal(sizes[p.Name], utf8.RuneCountInString(p.Chars), p.Name)
+
+		// Every preset must be accepted by the generator
+		_, err := nanoid.NewGenerator(nanoid.WithAlphabet(p.Chars))
+		is.NoError(err, p.Name)
+	}
+
+	// Presets returns a copy
+	Presets()[0].Chars = "changed"
+	p, ok := LookupPreset("numeric")
+	is.True(ok)
+	is.Equal("0123456789", p.Chars)
+
+	_, ok = LookupPreset("base64")
+	is.False(ok)
+}
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	tests := map[string]string{
+		"numeric":                   "0123456789",
+		"a-f":                       "abcdef",
+		"a-c,0-2":                   "abc012",
+		"a-z,0-9,-lookalikes":       "abcdefghijkmnpqrtwxyz346789",
+		"alphanumeric,-lookalikes":  "346789ABCDEFGHJKLMNPQRTUVWXYabcdefghijkmnpqrtwxyz",
+		"hex-lower,-a-c":            "0123456789def",
+		"a,b,c,c,b,a,d":             "abcd",
+		"ABC,CBA":                   "ABC",
+		`a\bc`:                      "abc",
+		"a-e,-c,c":                  "abcde",
+		`a-c,\-,\,`:                 "abc-,",
+		`a\-c`:                      "a-c",
+		"α-ε":                       "αβγδε",
+		"url-safe,-_-":              nanoid.DefaultAlphabet[2:],
+		"0-9,-numeric,x-z":          "xyz",
+		"base58,-base32-crockford,": "",
+	}
+
+	for expr, want := range tests {
+		got, err := Expand(expr)
+		if want == "" {
+			is.ErrorIs(err, ErrInvalidExpression, expr)
+			continue
+		}
+		is.NoError(err, expr)
+		is.Equal(want, got, expr)
+	}
+
+	// Presets expand to the same alphabet as the default url-safe alphabet
+	got, err := Expand("url-safe")
+	is.NoError(err)
+	is.Equal(nanoid.DefaultAlphabet, got)
+}
+
+func TestExpand_Invalid(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for _, expr := range []string{"", "a-c,,d", "-", "z-a", `abc\`, "\u0000-￿", "a\xff"} {
+		_, err := Expand(expr)
+		is.ErrorIs(err, ErrInvalidExpression, expr)
+	}
+
+	_, err := Expand("a-c,-a-c")
+	is.ErrorIs(err, ErrEmptyAlphabet)
+
+	for _, expr := range []string{"base64", "numeric,-lookalike", "a-z,xyz"} {
+		_, err = Expand(expr)
+		is.ErrorIs(err, ErrUnknownSet, expr)
+	}
+}
+
+func TestExpand_LowercaseLiterals(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// An unescaped lowercase term is taken for a misspelled name, and the error says how to write it literally
+	_, err := Expand("abc")
+	is.ErrorIs(err, ErrUnknownSet)
+	is.ErrorContains(err, `as in \abc,`)
+
+	for _, expr := range []string{`\abc`, `ab\c`, "a,b,c", "ABC,-A-C,a,b,c"} {
+		got, err := Expand(expr)
+		is.NoError(err, expr)
+		is.Equal("abc", got, expr)
+	}
+}
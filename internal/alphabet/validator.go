@@ -0,0 +1,101 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package alphabet provides helpers for working with Nano ID alphabets.
+package alphabet
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	"github.com/sixafter/nanoid"
+)
+
+var (
+	// ErrInvalidUTF8 is returned when an ID is not a valid UTF-8 string.
+	ErrInvalidUTF8 = errors.New("id contains invalid UTF-8")
+
+	// ErrLengthMismatch is returned when an ID does not have the expected number of characters.
+	ErrLengthMismatch = errors.New("id length mismatch")
+
+	// ErrInvalidCharacter is returned when an ID contains a character outside the alphabet.
+	ErrInvalidCharacter = errors.New("id contains a character outside the alphabet")
+)
+
+// Validator checks IDs against an alphabet and a fixed length.
+//
+// It is safe for concurrent use once constructed.
+type Validator struct {
+	alphabet string
+	runes    map[rune]struct{}
+	length   int
+}
+
+// NewValidator returns a Validator for IDs of the given length drawn from alphabet.
+//
+// The alphabet is checked by constructing a nanoid generator, so it is subject to
+// exactly the rules applied at generation time: valid UTF-8, no duplicate
+// characters, and between nanoid.MinAlphabetLength and nanoid.MaxAlphabetLength
+// runes, with multibyte runes counted as single characters.
+func NewValidator(alphabet string, length int) (*Validator, error) {
+	if length <= 0 || length > math.MaxUint16 {
+		return nil, nanoid.ErrInvalidLength
+	}
+
+	_, err := nanoid.NewGenerator(
+		nanoid.WithAlphabet(alphabet),
+		nanoid.WithLengthHint(uint16(length)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	runes := make(map[rune]struct{}, utf8.RuneCountInString(alphabet))
+	for _, r := range alphabet {
+		runes[r] = struct{}{}
+	}
+
+	return &Validator{
+		alphabet: alphabet,
+		runes:    runes,
+		length:   length,
+	}, nil
+}
+
+// Alphabet returns the alphabet IDs are validated against.
+func (v *Validator) Alphabet() string {
+	return v.alphabet
+}
+
+// Length returns the number of characters a valid ID must contain.
+func (v *Validator) Length() int {
+	return v.length
+}
+
+// Validate returns nil if id consists of exactly Length characters from the alphabet.
+//
+// The returned error wraps ErrInvalidUTF8, ErrLengthMismatch, or ErrInvalidCharacter
+// and describes the first problem found.
+func (v *Validator) Validate(id string) error {
+	if !utf8.ValidString(id) {
+		return ErrInvalidUTF8
+	}
+
+	if n := utf8.RuneCountInString(id); n != v.length {
+		return fmt.Errorf("%w: got %d characters, want %d", ErrLengthMismatch, n, v.length)
+	}
+
+	pos := 0
+	for _, r := range id {
+		pos++
+		if _, ok := v.runes[r]; !ok {
+			return fmt.Errorf("%w: %q at position %d", ErrInvalidCharacter, r, pos)
+		}
+	}
+
+	return nil
+}
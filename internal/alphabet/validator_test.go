@@ -0,0 +1,56 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package alphabet
+
+import (
+	"testing"
+
+	"github.com/sixafter/nanoid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewValidator_InvalidAlphabet(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	_, err := NewValidator("aab", 10)
+	is.ErrorIs(err, nanoid.ErrDuplicateCharacters)
+
+	_, err = NewValidator("a", 10)
+	is.ErrorIs(err, nanoid.ErrAlphabetTooShort)
+
+	_, err = NewValidator("ab\xff", 10)
+	is.ErrorIs(err, nanoid.ErrNonUTF8Alphabet)
+
+	_, err = NewValidator(nanoid.DefaultAlphabet, 0)
+	is.ErrorIs(err, nanoid.ErrInvalidLength)
+}
+
+func TestValidator_Validate(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	v, err := NewValidator("abc", 4)
+	is.NoError(err)
+
+	is.NoError(v.Validate("abca"))
+	is.ErrorIs(v.Validate("abc"), ErrLengthMismatch)
+	is.ErrorIs(v.Validate("abcd"), ErrInvalidCharacter)
+	is.ErrorIs(v.Validate("ab\xffc"), ErrInvalidUTF8)
+}
+
+func TestValidator_ValidateMultibyte(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	v, err := NewValidator("αβγδ", 3)
+	is.NoError(err)
+
+	// Lengths are measured in runes, not bytes
+	is.NoError(v.Validate("αβδ"))
+	is.ErrorIs(v.Validate("αβ"), ErrLengthMismatch)
+	is.ErrorContains(v.Validate("αbδ"), "position 2")
+}
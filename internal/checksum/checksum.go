@@ -0,0 +1,212 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package checksum computes and verifies check characters for Nano IDs. Check
+// characters are drawn from the ID's own alphabet, so checksummed IDs stay within
+// the same character set.
+//
+// Two schemes are supported:
+//
+//   - luhn appends one character using the Luhn mod N algorithm. It detects every
+//     single-character substitution and every adjacent transposition except that
+//     of the first and last characters of the alphabet. Doubling a character only
+//     maps the alphabet onto itself when it has an even number of characters, so
+//     luhn is refused for odd-sized alphabets.
+//   - weighted appends two characters chosen so that, over the whole ID with
+//     characters valued by their alphabet index and positions numbered from 1,
+//     both Σ aᵢ and Σ i·aᵢ are 0 mod N. It detects every single-character
+//     substitution and every adjacent transposition, including those that touch
+//     the check characters.
+package checksum
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sixafter/nanoid-cli/internal/alphabet"
+)
+
+// Supported checksum schemes.
+const (
+	// Luhn appends one check character computed with the Luhn mod N algorithm.
+	Luhn = "luhn"
+
+	// Weighted appends two check characters computed from plain and position-weighted sums.
+	Weighted = "weighted"
+)
+
+// Names lists the supported schemes in the order they are documented.
+var Names = []string{Luhn, Weighted}
+
+var (
+	// ErrUnknownScheme is returned when a checksum scheme is not supported.
+	ErrUnknownScheme = errors.New("unknown checksum scheme")
+
+	// ErrMismatch is returned when an ID's check characters do not match its body.
+	ErrMismatch = errors.New("checksum mismatch")
+
+	// ErrOddAlphabet is returned when luhn is used over an alphabet with an odd
+	// number of characters, where it would miss some substitutions.
+	ErrOddAlphabet = errors.New("luhn requires an alphabet with an even number of characters")
+)
+
+// Checksum computes and verifies check characters over a fixed alphabet.
+//
+// It is safe for concurrent use once constructed.
+type Checksum struct {
+	scheme   string
+	alphabet []rune
+	index    map[rune]int
+}
+
+// New returns a Checksum for the named scheme over alphabet. The alphabet is
+// expected to have been validated already, as by nanoid.WithAlphabet.
+func New(scheme, chars string) (*Checksum, error) {
+	if scheme != Luhn && scheme != Weighted {
+		return nil, fmt.Errorf("%w %q; must be one of: %s", ErrUnknownScheme, scheme, strings.Join(Names, ", "))
+	}
+
+	runes := []rune(chars)
+	index := make(map[rune]int, len(runes))
+	for i, r := range runes {
+		index[r] = i
+	}
+	if len(runes) < 2 || len(index) != len(runes) {
+		return nil, fmt.Errorf("checksum alphabet must have at least two distinct characters")
+	}
+	if scheme == Luhn && len(runes)%2 != 0 {
+		return nil, fmt.Errorf("%w, not %d; use %s instead", ErrOddAlphabet, len(runes), Weighted)
+	}
+
+	return &Checksum{scheme: scheme, alphabet: runes, index: index}, nil
+}
+
+// Scheme returns the name of the checksum scheme.
+func (c *Checksum) Scheme() string {
+	return c.scheme
+}
+
+// Size returns the number of check characters appended to an ID.
+func (c *Checksum) Size() int {
+	if c.scheme == Weighted {
+		return 2
+	}
+	return 1
+}
+
+// Append returns body followed by its check characters.
+func (c *Checksum) Append(body string) (string, error) {
+	values, err := c.values(body)
+	if err != nil {
+		return "", err
+	}
+
+	var check []int
+	if c.scheme == Weighted {
+		check = c.weighted(values)
+	} else {
+		check = []int{c.luhn(values)}
+	}
+
+	var b strings.Builder
+	b.Grow(len(body) + len(check)*utf8.UTFMax)
+	b.WriteString(body)
+	for _, v := range check {
+		b.WriteRune(c.alphabet[v])
+	}
+	return b.String(), nil
+}
+
+// Verify checks the trailing check characters of id and returns the body before them.
+func (c *Checksum) Verify(id string) (string, error) {
+	values, err := c.values(id)
+	if err != nil {
+		return "", err
+	}
+	if len(values) <= c.Size() {
+		return "", fmt.Errorf("%w: id is too short to carry %d check characters", alphabet.ErrLengthMismatch, c.Size())
+	}
+
+	n := len(c.alphabet)
+	body := values[:len(values)-c.Size()]
+	ok := true
+	if c.scheme == Weighted {
+		var sum, weightedSum int
+		for i, v := range values {
+			sum = (sum + v) % n
+			weightedSum = (weightedSum + (i+1)%n*v) % n
+		}
+		ok = sum == 0 && weightedSum == 0
+	} else {
+		ok = c.luhn(body) == values[len(values)-1]
+	}
+	if !ok {
+		return "", ErrMismatch
+	}
+	return c.Body(id), nil
+}
+
+// Body returns id without its trailing check characters, or "" if id is too short
+// to carry them. It does not verify the check characters.
+func (c *Checksum) Body(id string) string {
+	cut := len(id)
+	for range c.Size() {
+		if cut == 0 {
+			return ""
+		}
+		_, size := utf8.DecodeLastRuneInString(id[:cut])
+		cut -= size
+	}
+	return id[:cut]
+}
+
+// values maps the characters of s to their alphabet indexes.
+func (c *Checksum) values(s string) ([]int, error) {
+	if !utf8.ValidString(s) {
+		return nil, alphabet.ErrInvalidUTF8
+	}
+
+	values := make([]int, 0, len(s))
+	for i, r := range []rune(s) {
+		v, ok := c.index[r]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q at position %d", alphabet.ErrInvalidCharacter, r, i+1)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// luhn returns the Luhn mod N check value for body.
+func (c *Checksum) luhn(body []int) int {
+	n := len(c.alphabet)
+	factor, sum := 2, 0
+	for i := len(body) - 1; i >= 0; i-- {
+		addend := factor * body[i]
+		sum += addend/n + addend%n
+		factor = 3 - factor
+	}
+	return (n - sum%n) % n
+}
+
+// weighted returns the two check values that zero both the plain and the
+// position-weighted sum of the whole ID modulo the alphabet size.
+func (c *Checksum) weighted(body []int) []int {
+	n := len(c.alphabet)
+	var sum, weightedSum int
+	for i, v := range body {
+		sum = (sum + v) % n
+		weightedSum = (weightedSum + (i+1)%n*v) % n
+	}
+
+	// With p at position len+1 and q at len+2, solving
+	//   sum + p + q ≡ 0  and  weightedSum + (len+1)p + (len+2)q ≡ 0
+	// gives p ≡ weightedSum - (len+2)·sum and q ≡ -sum - p.
+	p := ((weightedSum-(len(body)+2)%n*sum)%n + n) % n
+	q := ((-sum-p)%n + 2*n) % n
+	return []int{p, q}
+}
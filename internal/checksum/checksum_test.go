@@ -0,0 +1,196 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package checksum
+
+import (
+	"testing"
+
+	"github.com/sixafter/nanoid"
+	"github.com/sixafter/nanoid-cli/internal/alphabet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecksum_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, chars := range []string{"01", "0123456789", nanoid.DefaultAlphabet, "αβγδεζηθ"} {
+		for _, scheme := range Names {
+			is := assert.New(t)
+
+			c, err := New(scheme, chars)
+			is.NoError(err)
+
+			gen, err := nanoid.NewGenerator(nanoid.WithAlphabet(chars))
+			is.NoError(err)
+
+			for range 100 {
+				body, err := gen.NewWithLength(12)
+				is.NoError(err)
+
+				id, err := c.Append(string(body))
+				is.NoError(err)
+				is.Equal(12+c.Size(), len([]rune(id)))
+
+				got, err := c.Verify(id)
+				is.NoError(err, "%s over %q: %s", scheme, chars, id)
+				is.Equal(string(body), got)
+			}
+		}
+	}
+}
+
+func TestChecksum_Luhn(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// Over the decimal alphabet Luhn mod N is the classic Luhn algorithm.
+	c, err := New(Luhn, "0123456789")
+	is.NoError(err)
+
+	id, err := c.Append("7992739871")
+	is.NoError(err)
+	is.Equal("79927398713", id)
+}
+
+// mutations returns every single-character substitution and every distinct
+// adjacent transposition of id.
+func mutations(id, chars string) []string {
+	runes := []rune(id)
+	var out []string
+	for i := range runes {
+		for _, r := range chars {
+			if r == runes[i] {
+				continue
+			}
+			m := append([]rune(nil), runes...)
+			m[i] = r
+			out = append(out, string(m))
+		}
+		if i+1 < len(runes) && runes[i] != runes[i+1] {
+			m := append([]rune(nil), runes...)
+			m[i], m[i+1] = m[i+1], m[i]
+			out = append(out, string(m))
+		}
+	}
+	return out
+}
+
+func TestChecksum_WeightedDetectsErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, chars := range []string{"01", "0123456789abcdef", nanoid.DefaultAlphabet} {
+		is := assert.New(t)
+
+		c, err := New(Weighted, chars)
+		is.NoError(err)
+
+		gen, err := nanoid.NewGenerator(nanoid.WithAlphabet(chars))
+		is.NoError(err)
+
+		for range 20 {
+			body, err := gen.NewWithLength(16)
+			is.NoError(err)
+
+			id, err := c.Append(string(body))
+			is.NoError(err)
+
+			for _, m := range mutations(id, chars) {
+				_, err := c.Verify(m)
+				is.ErrorIs(err, ErrMismatch, "Expected %q (from %q) to be rejected", m, id)
+			}
+		}
+	}
+}
+
+func TestChecksum_LuhnDetectsSubstitutions(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	const chars = "0123456789abcdef"
+	c, err := New(Luhn, chars)
+	is.NoError(err)
+
+	id, err := c.Append("3a9f01bc72e4d568")
+	is.NoError(err)
+
+	for _, m := range mutations(id, chars) {
+		runes, orig := []rune(m), []rune(id)
+		var diff []int
+		for i := range runes {
+			if runes[i] != orig[i] {
+				diff = append(diff, i)
+			}
+		}
+
+		// The one transposition Luhn mod N misses is that of the first and last characters.
+		if len(diff) == 2 && (orig[diff[0]] == '0' && orig[diff[1]] == 'f' || orig[diff[0]] == 'f' && orig[diff[1]] == '0') {
+			continue
+		}
+
+		_, err := c.Verify(m)
+		is.ErrorIs(err, ErrMismatch, "Expected %q to be rejected", m)
+	}
+}
+
+func TestChecksum_OddAlphabet(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	preset, ok := alphabet.LookupPreset("no-lookalikes")
+	is.True(ok)
+	is.Equal(1, len([]rune(preset.Chars))%2)
+
+	// Luhn misses substitutions over an odd-sized alphabet, so it is refused.
+	_, err := New(Luhn, preset.Chars)
+	is.ErrorIs(err, ErrOddAlphabet)
+	is.ErrorContains(err, "not 49; use weighted instead")
+
+	// Weighted detects every substitution and transposition regardless.
+	c, err := New(Weighted, preset.Chars)
+	is.NoError(err)
+
+	gen, err := nanoid.NewGenerator(nanoid.WithAlphabet(preset.Chars))
+	is.NoError(err)
+
+	for range 20 {
+		body, err := gen.NewWithLength(21)
+		is.NoError(err)
+
+		id, err := c.Append(string(body))
+		is.NoError(err)
+
+		for _, m := range mutations(id, preset.Chars) {
+			_, err := c.Verify(m)
+			is.ErrorIs(err, ErrMismatch, "Expected %q (from %q) to be rejected", m, id)
+		}
+	}
+}
+
+func TestChecksum_Invalid(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	_, err := New("crc", "abc")
+	is.ErrorIs(err, ErrUnknownScheme)
+
+	_, err = New(Luhn, "aa")
+	is.Error(err)
+
+	c, err := New(Weighted, "abc")
+	is.NoError(err)
+
+	_, err = c.Append("abz")
+	is.ErrorIs(err, alphabet.ErrInvalidCharacter)
+
+	is.Equal("abc", c.Body("abcab"))
+	is.Empty(c.Body("a"))
+
+	_, err = c.Verify("ab")
+	is.ErrorIs(err, alphabet.ErrLengthMismatch)
+
+	_, err = c.Verify("ab\xff")
+	is.ErrorIs(err, alphabet.ErrInvalidUTF8)
+}
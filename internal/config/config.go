@@ -0,0 +1,461 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package config layers settings from a YAML configuration file and NANOID_*
+// environment variables underneath command-line flags.
+//
+// A configuration file sets flags by name, either for every command that has the
+// flag or for a single command:
+//
+//	id-length: 12        # every command with --id-length
+//	preset: base58
+//	generate:            # generate only, taking precedence over the keys above
+//	  count: 5
+//	  format: ndjson
+//	bench:
+//	  alphabet: [url-safe, base58]
+//
+// The environment variable for a flag is NANOID_ followed by the flag name in
+// upper case with dashes replaced by underscores, such as NANOID_ID_LENGTH. A
+// command-specific variable such as NANOID_GENERATE_COUNT takes precedence.
+//
+// The profiles section defines the named profiles selected by --profile; see
+// package profile.
+//
+// A flag given on the command line always wins, then the selected profile, then
+// the environment, then the file, then the flag's default.
+//
+// A flag annotated with ValuesAnnotation accepts only the listed values. A
+// top-level key is checked against the values of every command it applies to, so
+// a key such as format, whose values differ between commands, must be set in a
+// command's section unless its value suits them all.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sixafter/nanoid-cli/internal/profile"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EnvPrefix starts the name of every environment variable that sets a flag.
+	EnvPrefix = "NANOID_"
+
+	// EnvConfig names the environment variable holding the path of the configuration file.
+	EnvConfig = EnvPrefix + "CONFIG"
+)
+
+// Origins of an effective setting.
+const (
+	OriginFlag    = "flag"
+	OriginProfile = "profile"
+	OriginEnv     = "env"
+	OriginFile    = "file"
+	OriginDefault = "default"
+)
+
+// ProfileFlag is the flag that selects a profile from the profiles section.
+const ProfileFlag = "profile"
+
+// profilesKey is the top-level key of the section that defines profiles.
+const profilesKey = "profiles"
+
+// mutuallyExclusiveAnnotation is the flag annotation Cobra uses to record the
+// groups set up by MarkFlagsMutuallyExclusive.
+const mutuallyExclusiveAnnotation = "cobra_annotation_mutually_exclusive"
+
+// ValuesAnnotation is the flag annotation listing the only values a flag accepts,
+// set with pflag.FlagSet.SetAnnotation, so that Check can reject values that a
+// command would only reject once it runs.
+const ValuesAnnotation = "nanoid_annotation_values"
+
+// ErrInvalidConfig is returned when a configuration file cannot be parsed or sets an unknown flag.
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// Config holds the settings read from a configuration file.
+type Config struct {
+	// Path is the file the configuration was read from, or empty if there was none.
+	Path string
+
+	global   map[string][]string
+	commands map[string]map[string][]string
+	profiles *profile.Set
+}
+
+// Setting is the effective value of a flag and where it came from.
+type Setting struct {
+	// Key is the flag name.
+	Key string `json:"key"`
+
+	// Value is the effective value.
+	Value string `json:"value"`
+
+	// Origin is one of OriginFlag, OriginProfile, OriginEnv, OriginFile, or OriginDefault.
+	Origin string `json:"origin"`
+
+	// Detail names the profile, environment variable, or file key the value came from.
+	Detail string `json:"detail,omitempty"`
+}
+
+// DefaultPath returns $XDG_CONFIG_HOME/nanoid/config.yaml, falling back to
+// ~/.config when XDG_CONFIG_HOME is not set.
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "nanoid", "config.yaml"), nil
+}
+
+// Load reads the configuration file at path. When path is empty, the file named
+// by NANOID_CONFIG or, failing that, the file at DefaultPath is read if it
+// exists; an explicitly named file must exist.
+func Load(path string) (*Config, error) {
+	explicit := true
+	if path == "" {
+		path = os.Getenv(EnvConfig)
+	}
+	if path == "" {
+		explicit = false
+		var err error
+		if path, err = DefaultPath(); err != nil {
+			return &Config{}, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	c.Path = path
+	return c, nil
+}
+
+// Parse parses a configuration from YAML.
+func Parse(data []byte) (*Config, error) {
+	c := &Config{global: map[string][]string{}, commands: map[string]map[string][]string{}}
+
+	var doc yaml.Node
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	if len(doc.Content) == 0 {
+		return c, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%w: line %d: expected a mapping of flag names to values", ErrInvalidConfig, root.Line)
+	}
+	for i := 0; i < len(root.Content); i += 2 {
+		key, value := root.Content[i].Value, root.Content[i+1]
+		if key == profilesKey {
+			profiles, err := parseProfiles(value)
+			if err != nil {
+				return nil, err
+			}
+			c.profiles = profiles
+			continue
+		}
+		if value.Kind != yaml.MappingNode {
+			values, err := parseValue(key, value)
+			if err != nil {
+				return nil, err
+			}
+			c.global[key] = values
+			continue
+		}
+
+		section := make(map[string][]string, len(value.Content)/2)
+		for j := 0; j < len(value.Content); j += 2 {
+			name := value.Content[j].Value
+			values, err := parseValue(key+"."+name, value.Content[j+1])
+			if err != nil {
+				return nil, err
+			}
+			section[name] = values
+		}
+		c.commands[key] = section
+	}
+
+	return c, nil
+}
+
+// parseProfiles parses the profiles section.
+func parseProfiles(node *yaml.Node) (*profile.Set, error) {
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	profiles, err := profile.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	return profiles, nil
+}
+
+// Profiles returns the profiles defined in the configuration file.
+func (c *Config) Profiles() *profile.Set {
+	return c.profiles
+}
+
+// parseValue returns the text of a scalar, or of every item of a sequence of scalars.
+func parseValue(key string, node *yaml.Node) ([]string, error) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return []string{node.Value}, nil
+	case yaml.SequenceNode:
+		values := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("%w: line %d: %s must be a list of plain values", ErrInvalidConfig, item.Line, key)
+			}
+			values = append(values, item.Value)
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("%w: line %d: %s must be a value or a list of values", ErrInvalidConfig, node.Line, key)
+	}
+}
+
+// Check reports keys that do not name a flag: top-level keys must be a flag of
+// at least one command, and every section must name a command and hold only
+// that command's flags. It also reports values that a flag annotated with
+// ValuesAnnotation does not accept, in the section that sets them or, for a
+// top-level key, in any command that has the flag.
+func (c *Config) Check(commands map[string]*pflag.FlagSet) error {
+	names := slices.Sorted(maps.Keys(commands))
+	for _, key := range slices.Sorted(maps.Keys(c.global)) {
+		found := false
+		for _, name := range names {
+			fs := commands[name]
+			if !settable(fs, key) {
+				continue
+			}
+			found = true
+			if err := accepts(fs.Lookup(key), c.global[key]); err != nil {
+				return fmt.Errorf("%w: %s: command %q: %s; set it in a command section instead", ErrInvalidConfig, c.Path, name, err)
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: %s: unknown flag %q", ErrInvalidConfig, c.Path, key)
+		}
+	}
+	for name, section := range c.commands {
+		fs, ok := commands[name]
+		if !ok {
+			return fmt.Errorf("%w: %s: unknown command %q", ErrInvalidConfig, c.Path, name)
+		}
+		for key, values := range section {
+			if !settable(fs, key) {
+				return fmt.Errorf("%w: %s: command %q has no flag %q", ErrInvalidConfig, c.Path, name, key)
+			}
+			if err := accepts(fs.Lookup(key), values); err != nil {
+				return fmt.Errorf("%w: %s: command %q: %s", ErrInvalidConfig, c.Path, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// accepts reports a value that f does not accept, if f is annotated with
+// ValuesAnnotation.
+func accepts(f *pflag.Flag, values []string) error {
+	allowed, ok := f.Annotations[ValuesAnnotation]
+	if !ok {
+		return nil
+	}
+	for _, v := range values {
+		if !slices.Contains(allowed, v) {
+			return fmt.Errorf("%s %q must be one of: %s", f.Name, v, strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
+
+// settable reports whether fs has a flag called name that configuration may set.
+func settable(fs *pflag.FlagSet, name string) bool {
+	return fs.Lookup(name) != nil && name != "help" && name != "config"
+}
+
+// EnvName returns the environment variable that sets the named flag, for every
+// command when command is empty or for that command only otherwise.
+func EnvName(command, flag string) string {
+	name := flag
+	if command != "" {
+		name = command + "_" + flag
+	}
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// Resolve returns the effective setting of every flag in fs for command, without
+// changing any of them. lookupEnv is typically os.LookupEnv.
+func (c *Config) Resolve(command string, fs *pflag.FlagSet, lookupEnv func(string) (string, bool)) ([]Setting, error) {
+	settings, _, err := c.plan(command, fs, lookupEnv)
+	return settings, err
+}
+
+// Apply sets every flag in fs that was not given on the command line from the
+// selected profile, the environment, or the configuration file, and returns the
+// effective settings.
+//
+// A flag is left at its default when a flag it is mutually exclusive with was
+// given on the command line or set by the profile, so that, for example, a
+// configured preset never overrides an explicit --alphabet. Applied flags are not
+// marked as changed.
+func (c *Config) Apply(command string, fs *pflag.FlagSet, lookupEnv func(string) (string, bool)) ([]Setting, error) {
+	settings, values, err := c.plan(command, fs, lookupEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, s := range settings {
+		if values[i] == nil {
+			continue
+		}
+		f := fs.Lookup(s.Key)
+		for _, v := range values[i] {
+			if err := f.Value.Set(v); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", s.Origin, s.Detail, err)
+			}
+		}
+		settings[i].Value = f.Value.String()
+	}
+	return settings, nil
+}
+
+// selection is the profile chosen by the profile flag, if any.
+type selection struct {
+	name     string
+	settings map[string]string
+}
+
+// plan returns the effective setting of every flag in fs for command, together
+// with the values to set on each flag that was not given on the command line.
+func (c *Config) plan(command string, fs *pflag.FlagSet, lookupEnv func(string) (string, bool)) ([]Setting, [][]string, error) {
+	chosen, err := c.selectProfile(command, fs, lookupEnv)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		settings []Setting
+		values   [][]string
+	)
+	fs.VisitAll(func(f *pflag.Flag) {
+		if !settable(fs, f.Name) {
+			return
+		}
+		if f.Changed {
+			settings = append(settings, Setting{Key: f.Name, Value: f.Value.String(), Origin: OriginFlag})
+			values = append(values, nil)
+			return
+		}
+
+		s, v := c.lookup(command, f, chosen, lookupEnv)
+		pinned := chosen.settings
+		if s.Origin == OriginProfile {
+			pinned = nil
+		}
+		if s.Origin != OriginDefault && excluded(fs, f, pinned) {
+			s, v = Setting{Key: f.Name, Value: f.DefValue, Origin: OriginDefault}, nil
+		}
+		settings = append(settings, s)
+		values = append(values, v)
+	})
+	return settings, values, nil
+}
+
+// selectProfile returns the profile named by the profile flag of fs, which may
+// itself come from the environment or the configuration file.
+func (c *Config) selectProfile(command string, fs *pflag.FlagSet, lookupEnv func(string) (string, bool)) (selection, error) {
+	f := fs.Lookup(ProfileFlag)
+	if f == nil {
+		return selection{}, nil
+	}
+
+	name := f.Value.String()
+	if !f.Changed {
+		s, _ := c.lookup(command, f, selection{}, lookupEnv)
+		name = s.Value
+	}
+	if name == "" {
+		return selection{}, nil
+	}
+
+	p, err := c.profiles.Lookup(name)
+	if err != nil {
+		return selection{}, err
+	}
+	return selection{name: p.Name, settings: p.Settings()}, nil
+}
+
+// lookup returns the setting of f for command from the selected profile, the
+// environment, the file, or its default, in that order of precedence, together
+// with the values to set.
+func (c *Config) lookup(command string, f *pflag.Flag, chosen selection, lookupEnv func(string) (string, bool)) (Setting, []string) {
+	if v, ok := chosen.settings[f.Name]; ok {
+		return Setting{Key: f.Name, Value: v, Origin: OriginProfile, Detail: chosen.name}, []string{v}
+	}
+
+	for _, name := range []string{EnvName(command, f.Name), EnvName("", f.Name)} {
+		if v, ok := lookupEnv(name); ok {
+			return Setting{Key: f.Name, Value: v, Origin: OriginEnv, Detail: name}, []string{v}
+		}
+	}
+
+	if values, ok := c.commands[command][f.Name]; ok {
+		return Setting{Key: f.Name, Value: strings.Join(values, ","), Origin: OriginFile, Detail: c.Path + ": " + command + "." + f.Name}, values
+	}
+	if values, ok := c.global[f.Name]; ok {
+		return Setting{Key: f.Name, Value: strings.Join(values, ","), Origin: OriginFile, Detail: c.Path + ": " + f.Name}, values
+	}
+
+	return Setting{Key: f.Name, Value: f.DefValue, Origin: OriginDefault}, nil
+}
+
+// excluded reports whether a flag that is mutually exclusive with f was given on
+// the command line or is among the pinned settings.
+func excluded(fs *pflag.FlagSet, f *pflag.Flag, pinned map[string]string) bool {
+	for _, group := range f.Annotations[mutuallyExclusiveAnnotation] {
+		for _, name := range strings.Fields(group) {
+			if name == f.Name {
+				continue
+			}
+			if _, ok := pinned[name]; ok {
+				return true
+			}
+			if other := fs.Lookup(name); other != nil && other.Changed {
+				return true
+			}
+		}
+	}
+	return false
+}
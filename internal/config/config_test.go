@@ -0,0 +1,300 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sixafter/nanoid-cli/internal/profile"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+// env returns a lookup function over a fixed set of variables.
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+// newCommand returns a command with a few flags of different kinds, with
+// --alphabet and --preset mutually exclusive, parsed from args.
+func newCommand(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+
+	cmd := &cobra.Command{Use: "generate", Run: func(*cobra.Command, []string) {}}
+	cmd.Flags().IntP("count", "c", 1, "")
+	cmd.Flags().Int("id-length", 21, "")
+	cmd.Flags().String("alphabet", "abc", "")
+	cmd.Flags().String("preset", "", "")
+	cmd.Flags().StringSlice("source", []string{"auto"}, "")
+	cmd.MarkFlagsMutuallyExclusive("alphabet", "preset")
+	assert.NoError(t, cmd.ParseFlags(args))
+	return cmd
+}
+
+// byKey indexes settings by flag name.
+func byKey(settings []Setting) map[string]Setting {
+	m := make(map[string]Setting, len(settings))
+	for _, s := range settings {
+		m[s.Key] = s
+	}
+	return m
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	c, err := Parse([]byte("id-length: 12\nsource: [chacha20, aes-ctr-drbg]\ngenerate:\n  count: 5\n"))
+	is.NoError(err)
+	is.Equal([]string{"12"}, c.global["id-length"])
+	is.Equal([]string{"chacha20", "aes-ctr-drbg"}, c.global["source"])
+	is.Equal([]string{"5"}, c.commands["generate"]["count"])
+
+	c, err = Parse(nil)
+	is.NoError(err, "An empty file is a valid configuration")
+	is.Empty(c.global)
+
+	for _, data := range []string{"- a\n- b\n", "count: [[1]]\n", "count: [1\n"} {
+		_, err = Parse([]byte(data))
+		is.True(errors.Is(err, ErrInvalidConfig), "Expected %q to be invalid, got %v", data, err)
+	}
+}
+
+func TestApply_Precedence(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	c, err := Parse([]byte("id-length: 12\ncount: 2\nsource: [chacha20, aes-ctr-drbg]\ngenerate:\n  count: 3\n"))
+	is.NoError(err)
+	c.Path = "config.yaml"
+
+	cmd := newCommand(t, "--id-length", "8")
+	settings, err := c.Apply("generate", cmd.Flags(), env(map[string]string{
+		"NANOID_ID_LENGTH": "10",
+		"NANOID_ALPHABET":  "xyz",
+	}))
+	is.NoError(err)
+
+	got := byKey(settings)
+	is.Equal(Setting{Key: "id-length", Value: "8", Origin: OriginFlag}, got["id-length"], "A flag wins over the environment")
+	is.Equal(Setting{Key: "alphabet", Value: "xyz", Origin: OriginEnv, Detail: "NANOID_ALPHABET"}, got["alphabet"])
+	is.Equal(Setting{Key: "count", Value: "3", Origin: OriginFile, Detail: "config.yaml: generate.count"}, got["count"],
+		"A command section wins over the top level")
+	is.Equal("[chacha20,aes-ctr-drbg]", got["source"].Value)
+	is.Equal(Setting{Key: "preset", Value: "", Origin: OriginDefault}, got["preset"])
+
+	count, _ := cmd.Flags().GetInt("count")
+	is.Equal(3, count)
+	sources, _ := cmd.Flags().GetStringSlice("source")
+	is.Equal([]string{"chacha20", "aes-ctr-drbg"}, sources)
+	is.False(cmd.Flags().Changed("count"), "Applied flags are not marked as changed")
+}
+
+func TestApply_CommandEnv(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	cmd := newCommand(t)
+	settings, err := (&Config{}).Apply("generate", cmd.Flags(), env(map[string]string{
+		"NANOID_COUNT":          "2",
+		"NANOID_GENERATE_COUNT": "4",
+	}))
+	is.NoError(err)
+	is.Equal("NANOID_GENERATE_COUNT", byKey(settings)["count"].Detail)
+
+	count, _ := cmd.Flags().GetInt("count")
+	is.Equal(4, count)
+}
+
+func TestApply_MutuallyExclusive(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	c, err := Parse([]byte("preset: base58\n"))
+	is.NoError(err)
+
+	cmd := newCommand(t, "--alphabet", "01")
+	settings, err := c.Apply("generate", cmd.Flags(), env(nil))
+	is.NoError(err)
+	is.Equal(OriginDefault, byKey(settings)["preset"].Origin)
+
+	preset, _ := cmd.Flags().GetString("preset")
+	is.Empty(preset, "A configured preset must not override an explicit --alphabet")
+	is.NoError(cmd.ValidateFlagGroups())
+}
+
+func TestApply_Invalid(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	cmd := newCommand(t)
+	_, err := (&Config{}).Apply("generate", cmd.Flags(), env(map[string]string{"NANOID_COUNT": "many"}))
+	is.ErrorContains(err, "env NANOID_COUNT")
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	c, err := Parse([]byte("count: 2\n"))
+	is.NoError(err)
+
+	cmd := newCommand(t)
+	settings, err := c.Resolve("generate", cmd.Flags(), env(nil))
+	is.NoError(err)
+	got := byKey(settings)
+	is.Equal("2", got["count"].Value)
+	is.Equal(OriginFile, got["count"].Origin)
+
+	count, _ := cmd.Flags().GetInt("count")
+	is.Equal(1, count, "Resolve must not change any flag")
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	sets := map[string]*pflag.FlagSet{"generate": newCommand(t).Flags()}
+	for data, valid := range map[string]bool{
+		"count: 1\ngenerate:\n  preset: base58\n": true,
+		"counts: 1\n":              false,
+		"inspect:\n  count: 1\n":   false,
+		"generate:\n  counts: 1\n": false,
+		"help: true\n":             false,
+	} {
+		c, err := Parse([]byte(data))
+		is.NoError(err)
+		if valid {
+			is.NoError(c.Check(sets), data)
+		} else {
+			is.True(errors.Is(c.Check(sets), ErrInvalidConfig), data)
+		}
+	}
+}
+
+func TestCheck_Values(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// format accepts different values in different commands
+	generate := pflag.NewFlagSet("generate", pflag.ContinueOnError)
+	generate.String("format", "text", "")
+	is.NoError(generate.SetAnnotation("format", ValuesAnnotation, []string{"text", "json", "ndjson"}))
+	bench := pflag.NewFlagSet("bench", pflag.ContinueOnError)
+	bench.String("format", "text", "")
+	is.NoError(bench.SetAnnotation("format", ValuesAnnotation, []string{"text", "json"}))
+	sets := map[string]*pflag.FlagSet{"generate": generate, "bench": bench}
+
+	for data, want := range map[string]string{
+		"format: json\n":                "",
+		"generate:\n  format: ndjson\n": "",
+		"format: ndjson\n":              `command "bench": format "ndjson" must be one of: text, json; set it in a command section instead`,
+		"bench:\n  format: ndjson\n":    `command "bench": format "ndjson" must be one of: text, json`,
+		"format: xml\n":                 `command "bench": format "xml" must be one of: text, json`,
+	} {
+		c, err := Parse([]byte(data))
+		is.NoError(err)
+		err = c.Check(sets)
+		if want == "" {
+			is.NoError(err, data)
+		} else {
+			is.ErrorIs(err, ErrInvalidConfig, data)
+			is.ErrorContains(err, want, data)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	is := assert.New(t)
+
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv(EnvConfig, "")
+
+	c, err := Load("")
+	is.NoError(err, "A missing default file is not an error")
+	is.Empty(c.Path)
+
+	path := filepath.Join(dir, "nanoid", "config.yaml")
+	is.NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+	is.NoError(os.WriteFile(path, []byte("count: 2\n"), 0o600))
+
+	c, err = Load("")
+	is.NoError(err)
+	is.Equal(path, c.Path)
+
+	_, err = Load(filepath.Join(dir, "missing.yaml"))
+	is.True(errors.Is(err, os.ErrNotExist), "A missing explicit file is an error")
+
+	t.Setenv(EnvConfig, filepath.Join(dir, "missing.yaml"))
+	_, err = Load("")
+	is.True(errors.Is(err, os.ErrNotExist), "A missing file named by %s is an error", EnvConfig)
+}
+
+func TestApply_Profile(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	c, err := Parse([]byte(`
+preset: base58
+count: 2
+profiles:
+  token:
+    alphabet: "0123456789"
+    id-length: 32
+`))
+	is.NoError(err)
+
+	cmd := newCommandWithProfile(t, "token", "--count", "5")
+
+	settings, err := c.Apply("generate", cmd.Flags(), env(map[string]string{"NANOID_ID_LENGTH": "10"}))
+	is.NoError(err)
+
+	got := byKey(settings)
+	is.Equal(Setting{Key: "id-length", Value: "32", Origin: OriginProfile, Detail: "token"}, got["id-length"],
+		"A profile wins over the environment")
+	is.Equal(Setting{Key: "alphabet", Value: "0123456789", Origin: OriginProfile, Detail: "token"}, got["alphabet"])
+	is.Equal(OriginDefault, got["preset"].Origin, "A profile's alphabet excludes a configured preset")
+	is.Equal(OriginFlag, got["count"].Origin)
+}
+
+func TestApply_ProfileOverridden(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	c, err := Parse([]byte("profiles:\n  short:\n    preset: numeric\n    id-length: 6\n"))
+	is.NoError(err)
+
+	cmd := newCommand(t, "--alphabet", "xyz")
+	cmd.Flags().String(ProfileFlag, "", "")
+
+	settings, err := c.Apply("generate", cmd.Flags(), env(map[string]string{"NANOID_PROFILE": "short"}))
+	is.NoError(err)
+
+	got := byKey(settings)
+	is.Equal(OriginProfile, got["id-length"].Origin, "The profile may be selected from the environment")
+	is.Equal(OriginFlag, got["alphabet"].Origin)
+	is.Equal(OriginDefault, got["preset"].Origin, "An explicit --alphabet excludes the profile's preset")
+
+	_, err = c.Apply("generate", newCommandWithProfile(t, "missing").Flags(), env(nil))
+	is.True(errors.Is(err, profile.ErrUnknownProfile))
+}
+
+// newCommandWithProfile returns a command from newCommand with --profile given as name.
+func newCommandWithProfile(t *testing.T, name string, args ...string) *cobra.Command {
+	t.Helper()
+	cmd := newCommand(t, args...)
+	cmd.Flags().String(ProfileFlag, "", "")
+	assert.NoError(t, cmd.Flags().Set(ProfileFlag, name))
+	return cmd
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package dedup
+
+import (
+	"math"
+)
+
+// bloom is a Bloom filter over 128-bit keys. The two halves of a key are used as
+// independent hashes and combined by double hashing to derive every probe.
+type bloom struct {
+	bits   []uint64
+	m      uint64
+	probes uint64
+}
+
+// newBloom returns a Bloom filter sized to hold n keys with a false positive rate of p.
+func newBloom(n int, p float64) *bloom {
+	n = max(n, 1)
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	m = max(m, 64)
+	probes := uint64(max(1, math.Round(float64(m)/float64(n)*math.Ln2)))
+
+	return &bloom{
+		bits:   make([]uint64, (m+63)/64),
+		m:      m,
+		probes: probes,
+	}
+}
+
+// add inserts k into the filter.
+func (b *bloom) add(k key) {
+	h1, h2 := k[0], k[1]|1
+	for i := range b.probes {
+		bit := (h1 + i*h2) % b.m
+		b.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// mayContain reports whether k may have been added. False means it definitely was not.
+func (b *bloom) mayContain(k key) bool {
+	h1, h2 := k[0], k[1]|1
+	for i := range b.probes {
+		bit := (h1 + i*h2) % b.m
+		if b.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
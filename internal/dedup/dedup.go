@@ -0,0 +1,173 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package dedup tracks the IDs emitted during a run so that duplicates can be
+// detected and regenerated.
+//
+// IDs are tracked as 128-bit keyed hashes. A Set starts out exact, holding every key
+// in memory. Past its memory limit it either switches to a Bloom filter, which keeps
+// memory bounded at the cost of occasionally reporting an unseen ID as seen, or, when
+// a spill directory is configured, writes sorted runs of keys to disk and stays exact.
+// Either way a Set never reports a seen ID as unseen, so callers that regenerate
+// whenever Add returns false are guaranteed to emit no duplicates; a false positive
+// only costs a needless regeneration.
+package dedup
+
+import (
+	"errors"
+	"fmt"
+	"hash/maphash"
+)
+
+// Modes a Set may be in, as reported by Set.Mode.
+const (
+	// ModeExact tracks every key in memory.
+	ModeExact = "exact"
+
+	// ModeBloom tracks keys in a Bloom filter that may report false positives.
+	ModeBloom = "bloom"
+
+	// ModeSpill tracks keys exactly in sorted runs spilled to disk.
+	ModeSpill = "spill"
+)
+
+// DefaultMemoryLimit is the default number of keys held in memory before a Set
+// switches to a Bloom filter or spills to disk.
+const DefaultMemoryLimit = 1 << 20
+
+// bloomFalsePositiveRate is the false positive rate of the Bloom filter used once a
+// Set without a spill directory exceeds its memory limit.
+const bloomFalsePositiveRate = 0.001
+
+// ErrInvalidOptions is returned by New when the options are out of range.
+var ErrInvalidOptions = errors.New("invalid deduplication options")
+
+// key is a 128-bit hash of an ID.
+type key [2]uint64
+
+// Options configures a Set.
+type Options struct {
+	// MemoryLimit is the number of keys held in memory before the Set switches to a
+	// Bloom filter or spills to disk. Zero uses DefaultMemoryLimit.
+	MemoryLimit int
+
+	// Expected is the total number of IDs expected, used to size the Bloom filter.
+	Expected int
+
+	// SpillDir, if set, is the directory sorted runs of keys are written to once
+	// MemoryLimit is reached, keeping the Set exact.
+	SpillDir string
+}
+
+// Set records IDs and reports whether each has been seen before. It is not safe for
+// concurrent use.
+type Set struct {
+	opts   Options
+	seeds  [2]maphash.Seed
+	exact  map[key]struct{}
+	filter *bloom
+	runs   []*run
+}
+
+// New returns an empty Set.
+func New(opts Options) (*Set, error) {
+	if opts.MemoryLimit < 0 || opts.Expected < 0 {
+		return nil, fmt.Errorf("%w: limits must not be negative", ErrInvalidOptions)
+	}
+	if opts.MemoryLimit == 0 {
+		opts.MemoryLimit = DefaultMemoryLimit
+	}
+
+	return &Set{
+		opts:  opts,
+		seeds: [2]maphash.Seed{maphash.MakeSeed(), maphash.MakeSeed()},
+		exact: make(map[key]struct{}, min(opts.Expected, opts.MemoryLimit)),
+	}, nil
+}
+
+// Add records id and reports whether it was new. A false result means id was (or,
+// in ModeBloom, may have been) added before.
+func (s *Set) Add(id string) (bool, error) {
+	k := key{maphash.String(s.seeds[0], id), maphash.String(s.seeds[1], id)}
+
+	if s.filter != nil {
+		if s.filter.mayContain(k) {
+			return false, nil
+		}
+		s.filter.add(k)
+		return true, nil
+	}
+
+	if _, ok := s.exact[k]; ok {
+		return false, nil
+	}
+	for _, r := range s.runs {
+		found, err := r.contains(k)
+		if err != nil {
+			return false, err
+		}
+		if found {
+			return false, nil
+		}
+	}
+
+	s.exact[k] = struct{}{}
+	if len(s.exact) >= s.opts.MemoryLimit {
+		if err := s.overflow(); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// overflow moves the in-memory keys to a spilled run or into a Bloom filter.
+func (s *Set) overflow() error {
+	keys := make([]key, 0, len(s.exact))
+	for k := range s.exact {
+		keys = append(keys, k)
+	}
+
+	if s.opts.SpillDir != "" {
+		r, err := writeRun(s.opts.SpillDir, keys)
+		if err != nil {
+			return fmt.Errorf("spilling to %s: %w", s.opts.SpillDir, err)
+		}
+		s.runs = append(s.runs, r)
+	} else {
+		s.filter = newBloom(max(s.opts.Expected, len(keys)), bloomFalsePositiveRate)
+		for _, k := range keys {
+			s.filter.add(k)
+		}
+	}
+
+	clear(s.exact)
+	if s.filter != nil {
+		s.exact = nil
+	}
+	return nil
+}
+
+// Mode reports how the Set is currently tracking keys.
+func (s *Set) Mode() string {
+	switch {
+	case s.filter != nil:
+		return ModeBloom
+	case len(s.runs) > 0:
+		return ModeSpill
+	default:
+		return ModeExact
+	}
+}
+
+// Close releases the Set, removing any files it spilled.
+func (s *Set) Close() error {
+	var errs []error
+	for _, r := range s.runs {
+		errs = append(errs, r.close())
+	}
+	s.runs = nil
+	return errors.Join(errs...)
+}
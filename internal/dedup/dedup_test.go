@@ -0,0 +1,114 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package dedup
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet_Exact(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	s, err := New(Options{Expected: 100})
+	is.NoError(err)
+	defer func() { is.NoError(s.Close()) }()
+
+	for i := range 100 {
+		added, err := s.Add(strconv.Itoa(i))
+		is.NoError(err)
+		is.True(added)
+	}
+	for i := range 100 {
+		added, err := s.Add(strconv.Itoa(i))
+		is.NoError(err)
+		is.False(added, "Expected a repeated ID to be reported as seen")
+	}
+	is.Equal(ModeExact, s.Mode())
+}
+
+func TestSet_Bloom(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	const n = 20000
+	s, err := New(Options{MemoryLimit: 1000, Expected: n})
+	is.NoError(err)
+	defer func() { is.NoError(s.Close()) }()
+
+	falsePositives := 0
+	for i := range n {
+		added, err := s.Add(strconv.Itoa(i))
+		is.NoError(err)
+		if !added {
+			falsePositives++
+		}
+	}
+	is.Equal(ModeBloom, s.Mode())
+	is.Less(falsePositives, n/100, "Expected few false positives")
+
+	// Never a false negative
+	for i := range n {
+		added, err := s.Add(strconv.Itoa(i))
+		is.NoError(err)
+		is.False(added)
+	}
+}
+
+func TestSet_Spill(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	dir := t.TempDir()
+	const n = 5000
+	s, err := New(Options{MemoryLimit: 700, Expected: n, SpillDir: dir})
+	is.NoError(err)
+
+	for i := range n {
+		added, err := s.Add(strconv.Itoa(i))
+		is.NoError(err)
+		is.True(added, "Expected spilling to stay exact")
+	}
+	is.Equal(ModeSpill, s.Mode())
+
+	for i := range n {
+		added, err := s.Add(strconv.Itoa(i))
+		is.NoError(err)
+		is.False(added)
+	}
+
+	entries, err := os.ReadDir(dir)
+	is.NoError(err)
+	is.Len(entries, n/700)
+
+	is.NoError(s.Close())
+	entries, err = os.ReadDir(dir)
+	is.NoError(err)
+	is.Empty(entries, "Expected Close to remove spilled runs")
+}
+
+func TestSet_SpillDirMissing(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	s, err := New(Options{MemoryLimit: 1, SpillDir: t.TempDir() + "/missing"})
+	is.NoError(err)
+
+	_, err = s.Add("a")
+	is.ErrorContains(err, "spilling to")
+}
+
+func TestNew_InvalidOptions(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	_, err := New(Options{MemoryLimit: -1})
+	is.ErrorIs(err, ErrInvalidOptions)
+}
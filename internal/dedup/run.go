@@ -0,0 +1,118 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package dedup
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"os"
+	"slices"
+	"sort"
+)
+
+// keySize is the size in bytes of a key stored in a run file.
+const keySize = 16
+
+// runBlockKeys is the number of keys between consecutive entries of a run's sparse
+// index, and so the number of keys read from disk by a single lookup.
+const runBlockKeys = 256
+
+// runFalsePositiveRate is the false positive rate of the Bloom filter kept for each
+// run; it bounds how often a lookup has to touch the disk for a key not in the run.
+const runFalsePositiveRate = 0.001
+
+// run is an immutable, sorted set of keys spilled to a file. Lookups consult an
+// in-memory Bloom filter first and then read a single block located through a
+// sparse index, so each run costs about two bytes of memory per key.
+type run struct {
+	file   *os.File
+	n      int
+	filter *bloom
+	index  []key
+}
+
+// writeRun sorts keys and writes them to a new file in dir.
+func writeRun(dir string, keys []key) (*run, error) {
+	slices.SortFunc(keys, compareKeys)
+
+	f, err := os.CreateTemp(dir, "nanoid-unique-*.run")
+	if err != nil {
+		return nil, err
+	}
+
+	r := &run{file: f, n: len(keys), filter: newBloom(len(keys), runFalsePositiveRate)}
+
+	w := bufio.NewWriter(f)
+	var buf [keySize]byte
+	for i, k := range keys {
+		if i%runBlockKeys == 0 {
+			r.index = append(r.index, k)
+		}
+		r.filter.add(k)
+
+		binary.BigEndian.PutUint64(buf[:8], k[0])
+		binary.BigEndian.PutUint64(buf[8:], k[1])
+		if _, err = w.Write(buf[:]); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		return nil, errors.Join(err, r.close())
+	}
+
+	return r, nil
+}
+
+// contains reports whether k is in the run.
+func (r *run) contains(k key) (bool, error) {
+	if !r.filter.mayContain(k) {
+		return false, nil
+	}
+
+	// Find the last block whose first key is <= k.
+	block := sort.Search(len(r.index), func(i int) bool { return compareKeys(r.index[i], k) > 0 }) - 1
+	if block < 0 {
+		return false, nil
+	}
+
+	start := block * runBlockKeys
+	count := min(runBlockKeys, r.n-start)
+	buf := make([]byte, count*keySize)
+	if _, err := r.file.ReadAt(buf, int64(start*keySize)); err != nil {
+		return false, err
+	}
+
+	_, found := sort.Find(count, func(i int) int {
+		rec := buf[i*keySize:]
+		return compareKeys(k, key{binary.BigEndian.Uint64(rec[:8]), binary.BigEndian.Uint64(rec[8:16])})
+	})
+	return found, nil
+}
+
+// close closes and removes the run's file.
+func (r *run) close() error {
+	return errors.Join(r.file.Close(), os.Remove(r.file.Name()))
+}
+
+// compareKeys orders keys by their high and then low half.
+func compareKeys(a, b key) int {
+	switch {
+	case a[0] < b[0]:
+		return -1
+	case a[0] > b[0]:
+		return 1
+	case a[1] < b[1]:
+		return -1
+	case a[1] > b[1]:
+		return 1
+	default:
+		return 0
+	}
+}
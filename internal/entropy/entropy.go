@@ -0,0 +1,87 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package entropy estimates the entropy and collision risk of Nano IDs.
+//
+// All calculations take the alphabet size as a character count, as reported by
+// nanoid.Config.AlphabetLen, so multibyte (Unicode) alphabets are measured in runes
+// rather than bytes. Keyspace sizes are handled in log space so that very long IDs
+// and large alphabets do not overflow.
+package entropy
+
+import (
+	"math"
+)
+
+// MaxLength is the longest ID length considered when searching for a minimum safe length.
+const MaxLength = math.MaxUint16
+
+// PerChar returns the bits of entropy contributed by one character drawn uniformly
+// from an alphabet of the given size.
+func PerChar(size int) float64 {
+	return math.Log2(float64(size))
+}
+
+// Bits returns the bits of entropy in an ID of the given length drawn uniformly
+// from an alphabet of the given size.
+func Bits(size, length int) float64 {
+	return PerChar(size) * float64(length)
+}
+
+// logKeyspace returns the natural logarithm of the number of distinct IDs.
+func logKeyspace(size, length int) float64 {
+	return float64(length) * math.Log(float64(size))
+}
+
+// Keyspace returns the number of distinct IDs of the given length, or +Inf if it
+// cannot be represented as a float64. The result is exact up to 2^53, so it can be
+// compared against ID counts.
+func Keyspace(size, length int) float64 {
+	return math.Pow(float64(size), float64(length))
+}
+
+// CollisionProbability returns the birthday-bound probability that at least two of
+// count IDs are equal:
+//
+//	p ≈ 1 - exp(-count·(count-1) / (2·size^length))
+func CollisionProbability(size, length int, count float64) float64 {
+	if count < 2 {
+		return 0
+	}
+
+	pairs := math.Log(count) + math.Log(count-1) - math.Ln2
+	x := math.Exp(pairs - logKeyspace(size, length))
+
+	// -expm1(-x) keeps full precision when x is tiny.
+	return -math.Expm1(-x)
+}
+
+// CountForProbability returns the number of IDs after which the probability of at
+// least one collision reaches p, or +Inf if that count overflows a float64:
+//
+//	count ≈ sqrt(2·size^length·ln(1/(1-p)))
+func CountForProbability(size, length int, p float64) float64 {
+	if p <= 0 {
+		return 0
+	}
+	if p >= 1 {
+		return math.Inf(1)
+	}
+
+	return math.Exp(0.5 * (math.Ln2 + logKeyspace(size, length) + math.Log(-math.Log1p(-p))))
+}
+
+// MinLength returns the shortest ID length for which generating count IDs keeps the
+// probability of at least one collision at or below p. It returns false if no length
+// up to MaxLength satisfies the target.
+func MinLength(size int, count, p float64) (int, bool) {
+	for length := 1; length <= MaxLength; length++ {
+		if CollisionProbability(size, length, count) <= p {
+			return length, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package entropy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBits(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	is.InDelta(6.0, PerChar(64), 1e-12)
+	is.InDelta(126.0, Bits(64, 21), 1e-9)
+
+	// Non-power-of-two alphabets contribute a fractional number of bits per character
+	is.InDelta(math.Log2(62)*10, Bits(62, 10), 1e-9)
+}
+
+func TestKeyspace(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	is.Equal(1000000.0, Keyspace(10, 6), "Expected exact keyspaces for integer comparison")
+	is.Equal(float64(1<<36), Keyspace(64, 6))
+	is.True(math.IsInf(Keyspace(256, 200), 1))
+}
+
+func TestCollisionProbability(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// Classic birthday problem: 23 people, 365 days ≈ 50%
+	is.InDelta(0.5, CollisionProbability(365, 1, 23), 0.01)
+
+	is.Zero(CollisionProbability(64, 21, 1))
+	is.Less(CollisionProbability(64, 21, 1e9), 1e-18)
+	is.Greater(CollisionProbability(64, 21, 1e9), 0.0)
+
+	// A keyspace far beyond float64 range must not overflow
+	is.Zero(CollisionProbability(256, 1000, 1e12))
+}
+
+func TestCountForProbability(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	n := CountForProbability(64, 8, 0.01)
+	is.InDelta(0.01, CollisionProbability(64, 8, n), 0.0005)
+
+	is.True(math.IsInf(CountForProbability(256, 1000, 0.01), 1))
+}
+
+func TestMinLength(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	length, ok := MinLength(10, 1e6, 1e-6)
+	is.True(ok)
+	is.LessOrEqual(CollisionProbability(10, length, 1e6), 1e-6)
+	is.Greater(CollisionProbability(10, length-1, 1e6), 1e-6)
+}
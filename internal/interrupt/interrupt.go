@@ -0,0 +1,138 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package interrupt stops long-running commands cleanly when the process receives
+// SIGINT or SIGTERM, or when its --timeout elapses, and maps the reason a command
+// stopped to its exit status.
+//
+// The reason is recorded as the cause of the command's context, so a command that
+// stops early returns context.Cause(ctx), which is ErrInterrupted or ErrTimeout.
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+var (
+	// ErrInterrupted is the cause of a context cancelled by SIGINT or SIGTERM.
+	ErrInterrupted = errors.New("interrupted")
+
+	// ErrTimeout is the cause of a context whose timeout elapsed.
+	ErrTimeout = errors.New("timed out")
+)
+
+// Annotation is the cobra command annotation that marks a command as stopping
+// cleanly once its context is done. Only annotated commands catch SIGINT and
+// SIGTERM; the rest keep the default behaviour, so the first signal terminates them.
+const Annotation = "nanoid.interruptible"
+
+// Exit statuses, following the shell's 128+SIGINT convention for interrupted
+// processes and the timeout utility's status for timed-out ones.
+const (
+	// ExitFailure is the status of any other failure.
+	ExitFailure = 1
+
+	// ExitTimeout is the status of a command stopped by its timeout.
+	ExitTimeout = 124
+
+	// ExitInterrupted is the status of a command stopped by SIGINT or SIGTERM.
+	ExitInterrupted = 130
+)
+
+// NotifyContext returns a copy of parent that is cancelled with ErrInterrupted on
+// the first SIGINT or SIGTERM. The signals are released as soon as one arrives, so
+// a second one terminates a command that is slow to stop. The returned function
+// releases them early and cancels the context.
+func NotifyContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			cancel(ErrInterrupted)
+		case <-ctx.Done():
+		}
+		signal.Stop(signals)
+	}()
+
+	return ctx, func() { cancel(context.Canceled) }
+}
+
+// WithTimeout returns a copy of parent that is cancelled with ErrTimeout once d
+// has elapsed. A zero d returns parent unchanged.
+func WithTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d == 0 {
+		return parent, func() {}
+	}
+	return context.WithTimeoutCause(parent, d, ErrTimeout)
+}
+
+// Reader returns a reader that reads from r until ctx is done and then fails with
+// context.Cause(ctx), even while a read from r is blocked, so a command waiting on
+// a slow pipe or a terminal still stops when it is interrupted. A blocked read is
+// abandoned rather than cancelled; its goroutine exits once r returns.
+func Reader(ctx context.Context, r io.Reader) io.Reader {
+	return &reader{ctx: ctx, r: r, results: make(chan readResult, 1)}
+}
+
+// reader reads from r on a separate goroutine so that Read can return once ctx is done.
+type reader struct {
+	ctx     context.Context
+	r       io.Reader
+	buf     []byte
+	results chan readResult
+}
+
+// readResult is the outcome of one read from the underlying reader.
+type readResult struct {
+	n   int
+	err error
+}
+
+// Read implements io.Reader. The underlying read fills a private buffer, so an
+// abandoned read never writes to p after Read has returned.
+func (r *reader) Read(p []byte) (int, error) {
+	if r.ctx.Err() != nil {
+		return 0, context.Cause(r.ctx)
+	}
+	if cap(r.buf) < len(p) {
+		r.buf = make([]byte, len(p))
+	}
+	buf := r.buf[:len(p)]
+
+	go func() {
+		n, err := r.r.Read(buf)
+		r.results <- readResult{n, err}
+	}()
+
+	select {
+	case res := <-r.results:
+		return copy(p, buf[:res.n]), res.err
+	case <-r.ctx.Done():
+		// The buffer may still be written by the abandoned read
+		r.buf = nil
+		return 0, context.Cause(r.ctx)
+	}
+}
+
+// ExitCode returns the exit status for a command that failed with err.
+func ExitCode(err error) int {
+	switch {
+	case errors.Is(err, ErrInterrupted):
+		return ExitInterrupted
+	case errors.Is(err, ErrTimeout):
+		return ExitTimeout
+	default:
+		return ExitFailure
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyContext(t *testing.T) {
+	is := assert.New(t)
+
+	ctx, stop := NotifyContext(context.Background())
+	defer stop()
+
+	is.NoError(syscall.Kill(syscall.Getpid(), syscall.SIGINT))
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the context to be cancelled by SIGINT")
+	}
+	is.ErrorIs(context.Cause(ctx), ErrInterrupted)
+}
+
+func TestNotifyContext_Stop(t *testing.T) {
+	is := assert.New(t)
+
+	ctx, stop := NotifyContext(context.Background())
+	stop()
+
+	<-ctx.Done()
+	is.ErrorIs(context.Cause(ctx), context.Canceled)
+}
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	parent := context.Background()
+	ctx, cancel := WithTimeout(parent, 0)
+	cancel()
+	is.Equal(parent, ctx, "Expected a zero timeout to leave the context unchanged")
+
+	ctx, cancel = WithTimeout(parent, time.Millisecond)
+	defer cancel()
+
+	<-ctx.Done()
+	is.ErrorIs(context.Cause(ctx), ErrTimeout)
+	is.ErrorIs(ctx.Err(), context.DeadlineExceeded)
+}
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	is.Equal(ExitInterrupted, ExitCode(fmt.Errorf("stopped: %w", ErrInterrupted)))
+	is.Equal(ExitTimeout, ExitCode(fmt.Errorf("stopped: %w", ErrTimeout)))
+	is.Equal(ExitFailure, ExitCode(errors.New("boom")))
+}
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// Reads pass through while the context is live
+	ctx, cancel := context.WithCancelCause(context.Background())
+	data, err := io.ReadAll(Reader(ctx, strings.NewReader("abc\ndef\n")))
+	is.NoError(err)
+	is.Equal("abc\ndef\n", string(data))
+
+	// A read blocked on a pipe that never delivers returns once the context is done
+	pr, pw := io.Pipe()
+	defer func() { _ = pw.Close() }()
+	time.AfterFunc(50*time.Millisecond, func() { cancel(ErrInterrupted) })
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := Reader(ctx, pr).Read(make([]byte, 16))
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		is.ErrorIs(err, ErrInterrupted)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the blocked read to return once the context was cancelled")
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package profile defines named ID policies: bundles of the alphabet, length,
+// prefix, random source, and output format that "generate --profile" applies.
+//
+// Profiles are defined in the profiles section of the configuration file:
+//
+//	profiles:
+//	  share-link:
+//	    description: Short links shared in chat
+//	    preset: no-lookalikes  # or alphabet: "...", optional
+//	    id-length: 10          # optional
+//	  api-token:
+//	    prefix: tok_
+//	    id-length: 32
+//	    source: ctr-drbg
+//	    format: text
+//
+// Each key other than description is the name of the generate flag it sets.
+package profile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sixafter/nanoid"
+	"github.com/sixafter/nanoid-cli/internal/alphabet"
+	"github.com/sixafter/nanoid-cli/internal/source"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrInvalidProfile is returned when a profile cannot be parsed or describes invalid IDs.
+	ErrInvalidProfile = errors.New("invalid profile")
+
+	// ErrUnknownProfile is returned when a profile name is not defined.
+	ErrUnknownProfile = errors.New("unknown profile")
+)
+
+// Profile is a named bundle of generate settings.
+type Profile struct {
+	// Name identifies the profile, such as "share-link".
+	Name string `yaml:"-" json:"name"`
+
+	// Description says what the profile is for.
+	Description string `yaml:"description" json:"description,omitempty"`
+
+	// Alphabet is the set of characters IDs are drawn from.
+	Alphabet string `yaml:"alphabet" json:"alphabet,omitempty"`
+
+	// Preset names a built-in alphabet or alphabet expression used instead of Alphabet.
+	Preset string `yaml:"preset" json:"preset,omitempty"`
+
+	// Length is the number of characters in each ID.
+	Length int `yaml:"id-length" json:"id_length,omitempty"`
+
+	// Prefix is written before every ID.
+	Prefix string `yaml:"prefix" json:"prefix,omitempty"`
+
+	// Source names the random source.
+	Source string `yaml:"source" json:"source,omitempty"`
+
+	// Format names the output format.
+	Format string `yaml:"format" json:"format,omitempty"`
+}
+
+// Keys lists the generate flags a profile may set, in the order they are documented.
+var Keys = []string{"alphabet", "preset", "id-length", "prefix", "source", "format"}
+
+// Set is a validated set of profiles.
+type Set struct {
+	profiles []Profile
+}
+
+// Parse parses a mapping of profile names to profiles from YAML and checks that
+// every profile is well formed. Use Validate to check that a profile's IDs can
+// be generated.
+func Parse(data []byte) (*Set, error) {
+	var profiles map[string]*Profile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&profiles); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProfile, err)
+	}
+
+	s := &Set{}
+	for name, p := range profiles {
+		if p == nil {
+			p = &Profile{}
+		}
+		p.Name = name
+
+		if err := p.check(); err != nil {
+			return nil, fmt.Errorf("%w: profile %q: %w", ErrInvalidProfile, name, err)
+		}
+		s.profiles = append(s.profiles, *p)
+	}
+
+	slices.SortFunc(s.profiles, func(a, b Profile) int { return strings.Compare(a.Name, b.Name) })
+	return s, nil
+}
+
+// check reports settings that are invalid whatever flags they are combined with.
+func (p *Profile) check() error {
+	if p.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	if p.Alphabet != "" && p.Preset != "" {
+		return errors.New("alphabet and preset are mutually exclusive")
+	}
+	if p.Length < 0 || p.Length > math.MaxUint16 {
+		return fmt.Errorf("id-length must be between 1 and %d", math.MaxUint16)
+	}
+	return nil
+}
+
+// Profiles returns every profile, sorted by name.
+func (s *Set) Profiles() []Profile {
+	if s == nil {
+		return nil
+	}
+	return slices.Clone(s.profiles)
+}
+
+// Lookup returns the profile with the given name.
+func (s *Set) Lookup(name string) (Profile, error) {
+	if s != nil {
+		for _, p := range s.profiles {
+			if p.Name == name {
+				return p, nil
+			}
+		}
+	}
+	return Profile{}, fmt.Errorf("%w: %q", ErrUnknownProfile, name)
+}
+
+// Settings returns the generate flags the profile sets, keyed by flag name.
+func (p Profile) Settings() map[string]string {
+	settings := make(map[string]string)
+	for name, value := range map[string]string{
+		"alphabet": p.Alphabet,
+		"preset":   p.Preset,
+		"prefix":   p.Prefix,
+		"source":   p.Source,
+		"format":   p.Format,
+	} {
+		if value != "" {
+			settings[name] = value
+		}
+	}
+	if p.Length != 0 {
+		settings["id-length"] = strconv.Itoa(p.Length)
+	}
+	return settings
+}
+
+// Validate builds the generator the profile describes, reporting errors such as
+// nanoid.ErrDuplicateCharacters that would otherwise surface on first use.
+// Formats lists the accepted output formats.
+func (p Profile) Validate(formats []string) error {
+	chars := p.Alphabet
+	if p.Preset != "" {
+		expanded, err := alphabet.Expand(p.Preset)
+		if err != nil {
+			return fmt.Errorf("preset: %w", err)
+		}
+		chars = expanded
+	}
+	if chars == "" {
+		chars = nanoid.DefaultAlphabet
+	}
+
+	length := p.Length
+	if length == 0 {
+		length = nanoid.DefaultLength
+	}
+
+	name := p.Source
+	if name == "" {
+		name = source.Auto
+	}
+	reader, _, err := source.New(name)
+	if err != nil {
+		return fmt.Errorf("source: %w", err)
+	}
+
+	if p.Format != "" && !slices.Contains(formats, p.Format) {
+		return fmt.Errorf("format must be one of: %s", strings.Join(formats, ", "))
+	}
+
+	generator, err := nanoid.NewGenerator(
+		nanoid.WithAlphabet(chars),
+		nanoid.WithLengthHint(uint16(length)),
+		nanoid.WithRandReader(reader),
+	)
+	if err != nil {
+		return fmt.Errorf("generator: %w", err)
+	}
+	if _, err = generator.NewWithLength(length); err != nil {
+		return fmt.Errorf("generator: %w", err)
+	}
+	return nil
+}
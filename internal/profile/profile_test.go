@@ -0,0 +1,83 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package profile
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sixafter/nanoid"
+	"github.com/sixafter/nanoid-cli/internal/source"
+	"github.com/stretchr/testify/assert"
+)
+
+var formats = []string{"text", "json"}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	s, err := Parse([]byte(`
+share-link:
+  description: Short links
+  preset: no-lookalikes
+  id-length: 10
+api-token:
+  prefix: tok_
+  source: crypto-rand
+  format: json
+empty:
+`))
+	is.NoError(err)
+
+	var names []string
+	for _, p := range s.Profiles() {
+		names = append(names, p.Name)
+	}
+	is.Equal([]string{"api-token", "empty", "share-link"}, names, "Profiles are sorted by name")
+
+	p, err := s.Lookup("share-link")
+	is.NoError(err)
+	is.Equal("Short links", p.Description)
+	is.Equal(map[string]string{"preset": "no-lookalikes", "id-length": "10"}, p.Settings())
+
+	p, err = s.Lookup("empty")
+	is.NoError(err)
+	is.Empty(p.Settings())
+
+	_, err = s.Lookup("missing")
+	is.True(errors.Is(err, ErrUnknownProfile))
+}
+
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for _, data := range []string{
+		"a:\n  length: 10\n",
+		"a:\n  alphabet: abc\n  preset: base58\n",
+		"a:\n  id-length: -1\n",
+		"a: [1, 2]\n",
+	} {
+		_, err := Parse([]byte(data))
+		is.True(errors.Is(err, ErrInvalidProfile), "Expected %q to be invalid, got %v", data, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	is.NoError(Profile{Name: "default"}.Validate(formats))
+	is.NoError(Profile{Name: "ok", Preset: "hex-lower", Length: 32, Source: source.CryptoRand, Format: "json"}.Validate(formats))
+
+	err := Profile{Name: "dup", Alphabet: "aabc"}.Validate(formats)
+	is.True(errors.Is(err, nanoid.ErrDuplicateCharacters), "Expected a duplicate character error, got %v", err)
+
+	is.ErrorContains(Profile{Name: "preset", Preset: "nope"}.Validate(formats), "preset")
+	is.True(errors.Is(Profile{Name: "source", Source: "nope"}.Validate(formats), source.ErrUnknownSource))
+	is.ErrorContains(Profile{Name: "format", Format: "xml"}.Validate(formats), "format must be one of: text, json")
+}
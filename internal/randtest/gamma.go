@@ -0,0 +1,72 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package randtest
+
+import "math"
+
+const (
+	// gammaEpsilon is the relative precision of the incomplete gamma evaluations.
+	gammaEpsilon = 1e-14
+
+	// gammaMaxIterations bounds the series and continued fraction evaluations.
+	gammaMaxIterations = 1000
+)
+
+// ChiSquareP returns the p-value of a χ² statistic with df degrees of freedom:
+// the probability that a χ²(df) variable is at least stat.
+func ChiSquareP(stat float64, df int) float64 {
+	if stat <= 0 {
+		return 1
+	}
+	return upperGamma(float64(df)/2, stat/2)
+}
+
+// upperGamma returns the regularized upper incomplete gamma function Q(a, x),
+// using the series for P(a, x) when x < a+1 and a continued fraction otherwise.
+func upperGamma(a, x float64) float64 {
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(a*math.Log(x) - x - lgamma)
+
+	if x < a+1 {
+		// P(a, x) = e^-x x^a / Γ(a) · Σ x^n / (a (a+1) ... (a+n))
+		term := 1 / a
+		sum := term
+		for n := 1; n < gammaMaxIterations; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*gammaEpsilon {
+				break
+			}
+		}
+		return math.Max(0, 1-prefix*sum)
+	}
+
+	// Modified Lentz evaluation of the continued fraction for Q(a, x).
+	const tiny = 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < gammaMaxIterations; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < gammaEpsilon {
+			break
+		}
+	}
+	return prefix * h
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package randtest runs statistical tests of uniformity and independence on a
+// sample of IDs, expressed as the alphabet indexes of their characters.
+//
+// The tests are:
+//
+//   - chi-square goodness of fit of the character frequencies at every position,
+//     and over all positions together;
+//   - serial correlation between consecutive characters;
+//   - a Wald–Wolfowitz runs test on whether each character falls in the lower or
+//     upper half of the alphabet;
+//   - a monobit-style frequency test on the same split, generalized to
+//     alphabets of any size, including those that are not powers of two.
+//
+// Each test yields a p-value: the probability of a result at least as extreme
+// from a truly uniform, independent source.
+package randtest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// DefaultAlpha is the default significance level below which a test fails.
+const DefaultAlpha = 0.01
+
+// ErrSampleTooSmall is returned when a sample is too small for the chi-square
+// tests to be meaningful.
+var ErrSampleTooSmall = errors.New("sample too small")
+
+// Result is the outcome of a single test.
+type Result struct {
+	// Name identifies the test, such as "chi-square/position-3".
+	Name string `json:"name"`
+
+	// Statistic is the test statistic: χ² for chi-square tests and a z-score otherwise.
+	Statistic float64 `json:"statistic"`
+
+	// PValue is the probability of a statistic at least this extreme from a uniform source.
+	PValue float64 `json:"p_value"`
+
+	// Threshold is the significance level the p-value is compared against.
+	Threshold float64 `json:"threshold"`
+
+	// Passed reports whether PValue is at least Threshold.
+	Passed bool `json:"passed"`
+}
+
+// Sample is a set of equal-length IDs over one alphabet.
+type Sample struct {
+	// Values holds the alphabet index of every character, ID after ID.
+	Values []byte
+
+	// Length is the number of characters in each ID.
+	Length int
+
+	// AlphabetSize is the number of characters in the alphabet.
+	AlphabetSize int
+}
+
+// MinCount returns the smallest number of IDs for which every chi-square cell
+// is expected to hold at least five observations.
+func MinCount(alphabetSize int) int {
+	return 5 * alphabetSize
+}
+
+// Run runs every test on s at significance level alpha. The per-position
+// chi-square tests share alpha through a Bonferroni correction, so that a
+// uniform source fails any of them with probability at most alpha.
+func Run(s Sample, alpha float64) ([]Result, error) {
+	if s.Length <= 0 || s.AlphabetSize < 2 || len(s.Values)%s.Length != 0 {
+		return nil, fmt.Errorf("invalid sample: %d values of length %d over %d characters", len(s.Values), s.Length, s.AlphabetSize)
+	}
+	if count := len(s.Values) / s.Length; count < MinCount(s.AlphabetSize) {
+		return nil, fmt.Errorf("%w: %d IDs; need at least %d for a %d-character alphabet", ErrSampleTooSmall, count, MinCount(s.AlphabetSize), s.AlphabetSize)
+	}
+
+	var results []Result
+	positionAlpha := alpha / float64(s.Length)
+	for pos := range s.Length {
+		stat, p := chiSquare(s, pos)
+		results = append(results, result(fmt.Sprintf("chi-square/position-%d", pos+1), stat, p, positionAlpha))
+	}
+
+	stat, p := chiSquare(s, -1)
+	results = append(results, result("chi-square/all", stat, p, alpha))
+
+	z := serialCorrelation(s)
+	results = append(results, result("serial-correlation", z, normalP(z), alpha))
+
+	z = runs(s)
+	results = append(results, result("runs", z, normalP(z), alpha))
+
+	z = monobit(s)
+	results = append(results, result("monobit", z, normalP(z), alpha))
+
+	return results, nil
+}
+
+// Passed reports whether every result passed.
+func Passed(results []Result) bool {
+	for _, r := range results {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// result builds a Result, treating NaN p-values as failures.
+func result(name string, stat, p, threshold float64) Result {
+	return Result{Name: name, Statistic: stat, PValue: p, Threshold: threshold, Passed: p >= threshold}
+}
+
+// chiSquare returns χ² and its p-value for the character frequencies at pos,
+// or over all positions when pos is negative.
+func chiSquare(s Sample, pos int) (float64, float64) {
+	counts := make([]float64, s.AlphabetSize)
+	n := 0.0
+	for i, v := range s.Values {
+		if pos < 0 || i%s.Length == pos {
+			counts[v]++
+			n++
+		}
+	}
+
+	expected := n / float64(s.AlphabetSize)
+	stat := 0.0
+	for _, c := range counts {
+		d := c - expected
+		stat += d * d / expected
+	}
+	return stat, ChiSquareP(stat, s.AlphabetSize-1)
+}
+
+// serialCorrelation returns the z-score of the lag-1 correlation between
+// consecutive characters, which is approximately normal with variance 1/n.
+func serialCorrelation(s Sample) float64 {
+	n := len(s.Values) - 1
+	var sumX, sumY, sumXX, sumYY, sumXY float64
+	for i := range n {
+		x, y := float64(s.Values[i]), float64(s.Values[i+1])
+		sumX += x
+		sumY += y
+		sumXX += x * x
+		sumYY += y * y
+		sumXY += x * y
+	}
+
+	fn := float64(n)
+	cov := sumXY - sumX*sumY/fn
+	varX := sumXX - sumX*sumX/fn
+	varY := sumYY - sumY*sumY/fn
+	if varX == 0 || varY == 0 {
+		return math.Inf(1)
+	}
+	return cov / math.Sqrt(varX*varY) * math.Sqrt(fn)
+}
+
+// upper reports whether v falls in the upper half of an alphabet of the given size.
+func upper(v byte, size int) bool {
+	return int(v) >= size/2
+}
+
+// runs returns the z-score of the Wald–Wolfowitz runs test on whether each
+// character falls in the lower or upper half of the alphabet.
+func runs(s Sample) float64 {
+	var n1, n2, runs float64
+	for i, v := range s.Values {
+		if upper(v, s.AlphabetSize) {
+			n1++
+		} else {
+			n2++
+		}
+		if i == 0 || upper(v, s.AlphabetSize) != upper(s.Values[i-1], s.AlphabetSize) {
+			runs++
+		}
+	}
+
+	n := n1 + n2
+	mean := 2*n1*n2/n + 1
+	variance := (mean - 1) * (mean - 2) / (n - 1)
+	if variance <= 0 {
+		return math.Inf(1)
+	}
+	return (runs - mean) / math.Sqrt(variance)
+}
+
+// monobit returns the z-score of the number of characters in the upper half of
+// the alphabet against its binomial expectation. For even alphabets this is the
+// classic monobit test on the top bit of each character's index.
+func monobit(s Sample) float64 {
+	ones := 0.0
+	for _, v := range s.Values {
+		if upper(v, s.AlphabetSize) {
+			ones++
+		}
+	}
+
+	n := float64(len(s.Values))
+	p := float64(s.AlphabetSize-s.AlphabetSize/2) / float64(s.AlphabetSize)
+	return (ones - n*p) / math.Sqrt(n*p*(1-p))
+}
+
+// normalP returns the two-sided p-value of a standard normal z-score.
+func normalP(z float64) float64 {
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package randtest
+
+import (
+	"testing"
+
+	"github.com/sixafter/nanoid"
+	"github.com/sixafter/nanoid-cli/internal/source"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChiSquareP(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// Critical values of the χ² distribution at the 5% and 1% levels.
+	tests := []struct {
+		stat float64
+		df   int
+		want float64
+	}{
+		{3.841459, 1, 0.05},
+		{6.634897, 1, 0.01},
+		{18.307038, 10, 0.05},
+		{82.528727, 63, 0.05},
+		{44.314105, 25, 0.01},
+		{0, 5, 1},
+	}
+
+	for _, tc := range tests {
+		is.InDelta(tc.want, ChiSquareP(tc.stat, tc.df), 1e-6, "χ²=%v df=%d", tc.stat, tc.df)
+	}
+}
+
+// sample generates count IDs of the given length over chars from a seeded
+// stream, so that the tests never fail by chance.
+func sample(t *testing.T, chars string, length, count int) Sample {
+	t.Helper()
+
+	reader, err := source.NewSeeded("randtest")
+	if err != nil {
+		t.Skipf("seeded source unavailable: %v", err)
+	}
+	gen, err := nanoid.NewGenerator(nanoid.WithAlphabet(chars), nanoid.WithRandReader(reader))
+	assert.NoError(t, err)
+
+	index := make(map[rune]byte)
+	for i, r := range []rune(chars) {
+		index[r] = byte(i)
+	}
+
+	s := Sample{Length: length, AlphabetSize: len(index)}
+	for range count {
+		id, err := gen.NewWithLength(length)
+		assert.NoError(t, err)
+		for _, r := range string(id) {
+			s.Values = append(s.Values, index[r])
+		}
+	}
+	return s
+}
+
+func TestRun_Uniform(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// A power-of-two alphabet and one that relies on rejection sampling.
+	for _, chars := range []string{"0123456789abcdef", "0123456789"} {
+		results, err := Run(sample(t, chars, 12, 20000), DefaultAlpha)
+		is.NoError(err)
+		is.Len(results, 12+4)
+		is.True(Passed(results), "Expected a uniform source to pass: %+v", results)
+	}
+}
+
+func TestRun_Biased(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	s := Sample{Length: 4, AlphabetSize: 10}
+	for i := range 10000 {
+		// Characters cycle through the alphabet, and digit 0 replaces 9 a third of the time.
+		for j := range 4 {
+			v := byte((i + j) % 10)
+			if v == 9 && i%3 == 0 {
+				v = 0
+			}
+			s.Values = append(s.Values, v)
+		}
+	}
+
+	results, err := Run(s, DefaultAlpha)
+	is.NoError(err)
+	is.False(Passed(results))
+
+	byName := make(map[string]Result)
+	for _, r := range results {
+		byName[r.Name] = r
+	}
+	is.False(byName["chi-square/all"].Passed)
+	is.False(byName["serial-correlation"].Passed, "Expected the cycle to be correlated")
+	is.False(byName["runs"].Passed, "Expected the cycle to form too few runs")
+}
+
+func TestRun_Invalid(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	_, err := Run(Sample{Values: make([]byte, 10*5), Length: 5, AlphabetSize: 64}, DefaultAlpha)
+	is.ErrorIs(err, ErrSampleTooSmall)
+
+	_, err = Run(Sample{Values: make([]byte, 7), Length: 5, AlphabetSize: 2}, DefaultAlpha)
+	is.Error(err)
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package registry loads the ID type registry: a YAML file mapping type names to
+// the prefix, separator, alphabet, and length of that type's IDs.
+//
+// A registry file looks like:
+//
+//	types:
+//	  order:
+//	    prefix: ord
+//	    separator: _       # optional, defaults to "_"
+//	    preset: base58     # or alphabet: "...", optional, defaults to the Nano ID alphabet
+//	    length: 16         # optional, defaults to 21
+//	    checksum: weighted # optional, appends check characters to the body
+package registry
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/sixafter/nanoid"
+	"github.com/sixafter/nanoid-cli/internal/alphabet"
+	"github.com/sixafter/nanoid-cli/internal/checksum"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSeparator separates the prefix from the body when a type does not set one.
+const DefaultSeparator = "_"
+
+var (
+	// ErrInvalidRegistry is returned when a registry file cannot be parsed or describes an invalid type.
+	ErrInvalidRegistry = errors.New("invalid type registry")
+
+	// ErrUnknownType is returned when a type name is not in the registry.
+	ErrUnknownType = errors.New("unknown ID type")
+
+	// ErrUnknownPrefix is returned when an ID does not start with the prefix of any registered type.
+	ErrUnknownPrefix = errors.New("id does not start with a registered type prefix")
+)
+
+// Type describes the shape of the IDs of one type.
+type Type struct {
+	// Name identifies the type, such as "order".
+	Name string `yaml:"-"`
+
+	// Prefix is written before every ID of the type, such as "ord".
+	Prefix string `yaml:"prefix"`
+
+	// Separator is written between the prefix and the body.
+	Separator string `yaml:"separator"`
+
+	// Alphabet is the set of characters the body is drawn from.
+	Alphabet string `yaml:"alphabet"`
+
+	// Preset names a built-in alphabet or alphabet expression used instead of Alphabet.
+	Preset string `yaml:"preset"`
+
+	// Length is the number of characters in the body.
+	Length int `yaml:"length"`
+
+	// Checksum names the scheme used to append check characters to the body, if any.
+	Checksum string `yaml:"checksum"`
+}
+
+// Lead returns the text written before the body: the prefix and the separator.
+func (t Type) Lead() string {
+	return t.Prefix + t.Separator
+}
+
+// file is the on-disk layout of a registry.
+type file struct {
+	Types map[string]*Type `yaml:"types"`
+}
+
+// Registry is a validated set of ID types.
+type Registry struct {
+	types []Type
+}
+
+// Load reads and parses the registry file at path.
+func Load(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Parse parses a registry from YAML, applying defaults and validating every type.
+func Parse(data []byte) (*Registry, error) {
+	var f file
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRegistry, err)
+	}
+	if len(f.Types) == 0 {
+		return nil, fmt.Errorf("%w: no types defined", ErrInvalidRegistry)
+	}
+
+	r := &Registry{}
+	leads := make(map[string]string, len(f.Types))
+	for name, t := range f.Types {
+		if t == nil {
+			t = &Type{}
+		}
+		t.Name = name
+
+		if err := t.normalize(); err != nil {
+			return nil, fmt.Errorf("%w: type %q: %w", ErrInvalidRegistry, name, err)
+		}
+		if other, ok := leads[t.Lead()]; ok {
+			return nil, fmt.Errorf("%w: types %q and %q share the prefix %q", ErrInvalidRegistry, other, name, t.Lead())
+		}
+		leads[t.Lead()] = name
+
+		r.types = append(r.types, *t)
+	}
+
+	slices.SortFunc(r.types, func(a, b Type) int { return strings.Compare(a.Name, b.Name) })
+	return r, nil
+}
+
+// normalize applies defaults to t and checks that it describes valid IDs.
+func (t *Type) normalize() error {
+	if t.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	if t.Prefix == "" {
+		return errors.New("prefix must not be empty")
+	}
+	if t.Separator == "" {
+		t.Separator = DefaultSeparator
+	}
+
+	switch {
+	case t.Alphabet != "" && t.Preset != "":
+		return errors.New("alphabet and preset are mutually exclusive")
+	case t.Preset != "":
+		expanded, err := alphabet.Expand(t.Preset)
+		if err != nil {
+			return err
+		}
+		t.Alphabet = expanded
+	case t.Alphabet == "":
+		t.Alphabet = nanoid.DefaultAlphabet
+	}
+
+	if t.Length == 0 {
+		t.Length = nanoid.DefaultLength
+	}
+	if t.Length < 0 || t.Length > math.MaxUint16 {
+		return fmt.Errorf("length must be between 1 and %d", math.MaxUint16)
+	}
+
+	// Reject alphabets the generator would reject, so --type never fails mid-run.
+	if _, err := alphabet.NewValidator(t.Alphabet, t.Length); err != nil {
+		return err
+	}
+
+	if t.Checksum != "" {
+		if _, err := checksum.New(t.Checksum, t.Alphabet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Types returns every registered type, sorted by name.
+func (r *Registry) Types() []Type {
+	return slices.Clone(r.types)
+}
+
+// Lookup returns the type with the given name.
+func (r *Registry) Lookup(name string) (Type, error) {
+	for _, t := range r.types {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return Type{}, fmt.Errorf("%w: %q", ErrUnknownType, name)
+}
+
+// Match returns the type whose prefix and separator start id, together with the
+// remaining body. When several types match, the one with the longest prefix wins.
+func (r *Registry) Match(id string) (Type, string, error) {
+	var (
+		best  Type
+		found bool
+	)
+	for _, t := range r.types {
+		if strings.HasPrefix(id, t.Lead()) && (!found || len(t.Lead()) > len(best.Lead())) {
+			best, found = t, true
+		}
+	}
+	if !found {
+		return Type{}, "", ErrUnknownPrefix
+	}
+
+	return best, strings.TrimPrefix(id, best.Lead()), nil
+}
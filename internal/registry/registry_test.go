@@ -0,0 +1,129 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sixafter/nanoid"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRegistry = `
+types:
+  order:
+    prefix: ord
+    preset: base58
+    length: 16
+  user:
+    prefix: usr
+  user-admin:
+    prefix: usr_adm
+    separator: "-"
+    alphabet: abcdef
+    length: 8
+    checksum: weighted
+`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := Parse([]byte(testRegistry))
+	is.NoError(err)
+
+	types := r.Types()
+	is.Len(types, 3)
+	is.Equal([]string{"order", "user", "user-admin"}, []string{types[0].Name, types[1].Name, types[2].Name})
+
+	order, err := r.Lookup("order")
+	is.NoError(err)
+	is.Equal("ord_", order.Lead())
+	is.Equal("123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz", order.Alphabet)
+	is.Equal(16, order.Length)
+
+	user, err := r.Lookup("user")
+	is.NoError(err)
+	is.Equal(DefaultSeparator, user.Separator)
+	is.Equal(nanoid.DefaultAlphabet, user.Alphabet)
+	is.Equal(nanoid.DefaultLength, user.Length)
+
+	_, err = r.Lookup("invoice")
+	is.ErrorIs(err, ErrUnknownType)
+}
+
+func TestRegistry_Match(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := Parse([]byte(testRegistry))
+	is.NoError(err)
+
+	typ, body, err := r.Match("ord_abc")
+	is.NoError(err)
+	is.Equal("order", typ.Name)
+	is.Equal("abc", body)
+
+	// The longest matching prefix wins
+	typ, body, err = r.Match("usr_adm-abcdef12")
+	is.NoError(err)
+	is.Equal("user-admin", typ.Name)
+	is.Equal("abcdef12", body)
+	is.Equal("weighted", typ.Checksum)
+
+	typ, body, err = r.Match("usr_xyz")
+	is.NoError(err)
+	is.Equal("user", typ.Name)
+	is.Equal("xyz", body)
+
+	_, _, err = r.Match("inv_abc")
+	is.ErrorIs(err, ErrUnknownPrefix)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"empty":            ``,
+		"no types":         `types: {}`,
+		"unknown field":    "types:\n  order:\n    prefix: ord\n    size: 4\n",
+		"missing prefix":   "types:\n  order:\n    length: 4\n",
+		"bad alphabet":     "types:\n  order:\n    prefix: ord\n    alphabet: aab\n",
+		"bad preset":       "types:\n  order:\n    prefix: ord\n    preset: base64\n",
+		"both alphabets":   "types:\n  order:\n    prefix: ord\n    preset: base58\n    alphabet: abc\n",
+		"negative length":  "types:\n  order:\n    prefix: ord\n    length: -1\n",
+		"bad checksum":     "types:\n  order:\n    prefix: ord\n    checksum: crc\n",
+		"duplicate prefix": "types:\n  a:\n    prefix: x\n  b:\n    prefix: x\n",
+		"not yaml":         "types: [",
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := assert.New(t)
+
+			_, err := Parse([]byte(data))
+			is.ErrorIs(err, ErrInvalidRegistry)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "types.yaml")
+	is.NoError(os.WriteFile(path, []byte(testRegistry), 0o600))
+
+	r, err := Load(path)
+	is.NoError(err)
+	is.Len(r.Types(), 3)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	is.ErrorIs(err, os.ErrNotExist)
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package selftest runs known-answer and health checks against the random sources
+// used for Nano ID generation.
+package selftest
+
+import (
+	"bytes"
+	"crypto/fips140"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"time"
+
+	"github.com/sixafter/aes-ctr-drbg"
+	"github.com/sixafter/nanoid-cli/internal/source"
+	"golang.org/x/crypto/chacha20"
+)
+
+// Result statuses.
+const (
+	// StatusPass marks a check that succeeded.
+	StatusPass = "pass"
+
+	// StatusFail marks a check that failed.
+	StatusFail = "fail"
+
+	// StatusSkip marks a check that does not apply to the current environment.
+	StatusSkip = "skip"
+)
+
+// sampleSize is the number of bytes read from each random source during its health check.
+const sampleSize = 4096
+
+// blockSize is the size of the blocks compared by the repetition (stuck output) check.
+const blockSize = 16
+
+// maxOnesDeviation is the largest tolerated deviation of the proportion of one bits
+// from 1/2 in a health-check sample. For 32,768 bits the standard deviation is about
+// 0.0028, so this bound only trips on grossly broken output.
+const maxOnesDeviation = 0.05
+
+// Result describes the outcome of a single check.
+type Result struct {
+	// Name identifies the check.
+	Name string `json:"name"`
+
+	// Status is StatusPass, StatusFail, or StatusSkip.
+	Status string `json:"status"`
+
+	// Detail explains what was checked, or why the check failed or was skipped.
+	Detail string `json:"detail"`
+
+	// Duration is how long the check took.
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Report is the outcome of a complete self-test run.
+type Report struct {
+	// Passed is true when no check failed.
+	Passed bool `json:"passed"`
+
+	// FIPS140 reports whether FIPS 140 mode was enabled during the run.
+	FIPS140 bool `json:"fips140"`
+
+	// Results holds the outcome of every check in the order they ran.
+	Results []Result `json:"results"`
+}
+
+// check is a single named self-test. It returns a description of what passed, or an error.
+type check struct {
+	name string
+	run  func() (string, error)
+}
+
+// errSkipped is returned by a check that does not apply to the current environment.
+var errSkipped = errors.New("skipped")
+
+// Run executes every self-test and returns the report. Checks continue to run after a
+// failure so that the report is complete.
+func Run() Report {
+	checks := []check{
+		{name: "aes-ctr-kat", run: aesCTRKnownAnswer},
+		{name: "chacha20-kat", run: chaCha20KnownAnswer},
+	}
+
+	for _, name := range []string{source.CryptoRand, source.ChaCha20, source.CTRDRBG} {
+		checks = append(checks, check{
+			name: "source/" + name,
+			run:  func() (string, error) { return sourceHealth(name) },
+		})
+	}
+
+	report := Report{Passed: true, FIPS140: fips140.Enabled()}
+	for _, c := range checks {
+		start := time.Now()
+		detail, err := c.run()
+		result := Result{Name: c.name, Status: StatusPass, Detail: detail, Duration: time.Since(start)}
+
+		switch {
+		case errors.Is(err, errSkipped):
+			result.Status = StatusSkip
+			result.Detail = err.Error()
+		case err != nil:
+			result.Status = StatusFail
+			result.Detail = err.Error()
+			report.Passed = false
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
+
+// aesCTRKnownAnswer runs the AES-CTR known-answer tests built into the DRBG package.
+func aesCTRKnownAnswer() (string, error) {
+	if err := ctrdrbg.RunSelfTests(); err != nil {
+		return "", err
+	}
+	return "NIST SP 800-38A F.5.5 AES-256-CTR known-answer test", nil
+}
+
+// chaCha20KnownAnswer checks the ChaCha20 block function against RFC 8439, Section 2.3.2.
+func chaCha20KnownAnswer() (string, error) {
+	key := []byte{
+		0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07,
+		0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f,
+		0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17,
+		0x18, 0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f,
+	}
+	nonce := []byte{
+		0x00, 0x00, 0x00, 0x09, 0x00, 0x00, 0x00, 0x4a,
+		0x00, 0x00, 0x00, 0x00,
+	}
+	expected := []byte{
+		0x10, 0xf1, 0xe7, 0xe4, 0xd1, 0x3b, 0x59, 0x15,
+		0x50, 0x0f, 0xdd, 0x1f, 0xa3, 0x20, 0x71, 0xc4,
+		0xc7, 0xd1, 0xf4, 0xc7, 0x33, 0xc0, 0x68, 0x03,
+		0x04, 0x22, 0xaa, 0x9a, 0xc3, 0xd4, 0x6c, 0x4e,
+		0xd2, 0x82, 0x64, 0x46, 0x07, 0x9f, 0xaa, 0x09,
+		0x14, 0xc2, 0xd7, 0x05, 0xd9, 0x8b, 0x02, 0xa2,
+		0xb5, 0x12, 0x9c, 0xd1, 0xde, 0x16, 0x4e, 0xb9,
+		0xcb, 0xd0, 0x83, 0xe8, 0xa2, 0x50, 0x3c, 0x4e,
+	}
+
+	c, err := chacha20.NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		return "", err
+	}
+	c.SetCounter(1)
+
+	keystream := make([]byte, len(expected))
+	c.XORKeyStream(keystream, keystream)
+	if !bytes.Equal(keystream, expected) {
+		return "", errors.New("ChaCha20 block function output does not match RFC 8439 test vector")
+	}
+
+	return "RFC 8439 section 2.3.2 ChaCha20 block function known-answer test", nil
+}
+
+// sourceHealth instantiates the named random source and checks a sample of its output
+// for stuck (repeated) blocks and grossly biased bits.
+func sourceHealth(name string) (string, error) {
+	if name == source.ChaCha20 && fips140.Enabled() {
+		return "", fmt.Errorf("%w: %s is not permitted in FIPS 140 mode", errSkipped, name)
+	}
+
+	reader, _, err := source.New(name)
+	if err != nil {
+		return "", err
+	}
+
+	return Health(reader)
+}
+
+// Health reads a sample from r and checks it for stuck (repeated) output blocks and
+// grossly biased bits, in the spirit of the NIST SP 800-90A continuous health test.
+func Health(r io.Reader) (string, error) {
+	sample := make([]byte, sampleSize)
+	if _, err := io.ReadFull(r, sample); err != nil {
+		return "", fmt.Errorf("reading sample: %w", err)
+	}
+
+	for i := blockSize; i < len(sample); i += blockSize {
+		if bytes.Equal(sample[i-blockSize:i], sample[i:i+blockSize]) {
+			return "", fmt.Errorf("repeated %d-byte output block at offset %d", blockSize, i)
+		}
+	}
+
+	ones := 0
+	for _, b := range sample {
+		ones += bits.OnesCount8(b)
+	}
+
+	proportion := float64(ones) / float64(len(sample)*8)
+	if proportion < 0.5-maxOnesDeviation || proportion > 0.5+maxOnesDeviation {
+		return "", fmt.Errorf("proportion of one bits %.4f is outside [%.2f, %.2f]",
+			proportion, 0.5-maxOnesDeviation, 0.5+maxOnesDeviation)
+	}
+
+	return fmt.Sprintf("%d-byte sample: no repeated %d-byte blocks, %.4f one bits", sampleSize, blockSize, proportion), nil
+}
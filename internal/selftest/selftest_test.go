@@ -0,0 +1,58 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package selftest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	report := Run()
+	is.True(report.Passed, "Expected every self-test to pass: %+v", report.Results)
+	is.Len(report.Results, 5)
+
+	for _, r := range report.Results {
+		is.NotEqual(StatusFail, r.Status, "Expected %s not to fail: %s", r.Name, r.Detail)
+		is.NotEmpty(r.Detail)
+	}
+}
+
+func TestHealth_Random(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	_, err := Health(rand.Reader)
+	is.NoError(err)
+}
+
+func TestHealth_StuckOutput(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	_, err := Health(bytes.NewReader(bytes.Repeat([]byte{0xa5}, sampleSize)))
+	is.ErrorContains(err, "repeated 16-byte output block")
+}
+
+func TestHealth_BiasedOutput(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// A counter never repeats a block but is heavily biased towards zero bits
+	sample := make([]byte, sampleSize)
+	for i := 0; i < len(sample); i += blockSize {
+		sample[i] = byte(i / blockSize)
+	}
+
+	_, err := Health(bytes.NewReader(sample))
+	is.ErrorContains(err, "proportion of one bits")
+}
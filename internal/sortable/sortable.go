@@ -0,0 +1,182 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package sortable encodes the time-ordered lead of sortable IDs.
+//
+// The lead packs a millisecond Unix timestamp and a sequence number into one
+// 64-bit value, timestamp<<SequenceBits | sequence, and writes it as a fixed
+// number of digits drawn from the ID's alphabet sorted by code point. Because
+// UTF-8 preserves code point order, leads compare bytewise in the order they
+// were issued, so the random body that follows never affects the sort order.
+package sortable
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// SequenceBits is the number of low bits of the lead that hold the sequence
+// number of IDs issued within the same millisecond.
+const SequenceBits = 16
+
+const (
+	// maxSequence is the largest sequence number before the clock borrows the next millisecond.
+	maxSequence = 1<<SequenceBits - 1
+
+	// maxMillis is the largest timestamp that fits above the sequence bits.
+	maxMillis = 1<<(64-SequenceBits) - 1
+)
+
+// ErrInvalidLead is returned when an ID does not start with a decodable timestamp lead.
+var ErrInvalidLead = errors.New("id does not start with a sortable timestamp")
+
+// Stamp is the decoded lead of a sortable ID.
+type Stamp struct {
+	// Time is the millisecond at which the ID was issued.
+	Time time.Time
+
+	// Sequence orders the IDs issued within the same millisecond.
+	Sequence uint16
+}
+
+// Encoding writes and reads leads using the digits of one alphabet.
+type Encoding struct {
+	digits []rune
+	index  map[rune]uint64
+	width  int
+}
+
+// NewEncoding returns an Encoding whose digits are the characters of alphabet
+// sorted by code point.
+func NewEncoding(alphabet string) (*Encoding, error) {
+	if !utf8.ValidString(alphabet) {
+		return nil, errors.New("alphabet contains invalid UTF-8")
+	}
+
+	digits := []rune(alphabet)
+	slices.Sort(digits)
+	if len(digits) < 2 || len(slices.Compact(slices.Clone(digits))) != len(digits) {
+		return nil, errors.New("sortable alphabet must have at least two distinct characters")
+	}
+
+	index := make(map[rune]uint64, len(digits))
+	for i, r := range digits {
+		index[r] = uint64(i)
+	}
+
+	// The lead is wide enough for every 64-bit value: the smallest width with
+	// base^width > math.MaxUint64.
+	base := uint64(len(digits))
+	width, capacity := 0, uint64(1)
+	for {
+		width++
+		hi, lo := bits.Mul64(capacity, base)
+		if hi != 0 {
+			break
+		}
+		capacity = lo
+	}
+
+	return &Encoding{digits: digits, index: index, width: width}, nil
+}
+
+// Width returns the number of characters in a lead.
+func (e *Encoding) Width() int {
+	return e.width
+}
+
+// Encode returns the lead for the given millisecond timestamp and sequence number.
+func (e *Encoding) Encode(millis int64, sequence uint16) string {
+	v := uint64(min(max(millis, 0), maxMillis))<<SequenceBits | uint64(sequence)
+
+	base := uint64(len(e.digits))
+	lead := make([]rune, e.width)
+	for i := e.width - 1; i >= 0; i-- {
+		lead[i] = e.digits[v%base]
+		v /= base
+	}
+	return string(lead)
+}
+
+// Decode reads the lead at the start of id.
+func (e *Encoding) Decode(id string) (Stamp, error) {
+	base := uint64(len(e.digits))
+
+	var v uint64
+	n := 0
+	for _, r := range id {
+		if n == e.width {
+			break
+		}
+		d, ok := e.index[r]
+		if !ok {
+			return Stamp{}, fmt.Errorf("%w: %q is not in the alphabet", ErrInvalidLead, r)
+		}
+		hi, lo := bits.Mul64(v, base)
+		sum, carry := bits.Add64(lo, d, 0)
+		if hi != 0 || carry != 0 {
+			return Stamp{}, fmt.Errorf("%w: value overflows 64 bits", ErrInvalidLead)
+		}
+		v = sum
+		n++
+	}
+	if n < e.width {
+		return Stamp{}, fmt.Errorf("%w: id is shorter than the %d-character lead", ErrInvalidLead, e.width)
+	}
+
+	return Stamp{
+		Time:     time.UnixMilli(int64(v >> SequenceBits)).UTC(),
+		Sequence: uint16(v & maxSequence),
+	}, nil
+}
+
+// Clock issues strictly increasing leads.
+//
+// It is safe for concurrent use.
+type Clock struct {
+	enc *Encoding
+	now func() time.Time
+
+	mu       sync.Mutex
+	millis   int64
+	sequence uint32
+}
+
+// NewClock returns a Clock that issues leads with enc using the system clock.
+func NewClock(enc *Encoding) *Clock {
+	return &Clock{enc: enc, now: time.Now, millis: math.MinInt64}
+}
+
+// Width returns the number of characters in the leads the clock issues.
+func (c *Clock) Width() int {
+	return c.enc.Width()
+}
+
+// Next returns the next lead. IDs issued within the same millisecond take
+// increasing sequence numbers; once those run out, or if the system clock steps
+// backwards, the clock keeps counting from the last millisecond it issued, so
+// leads never repeat or decrease.
+func (c *Clock) Next() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now().UnixMilli()
+	switch {
+	case now > c.millis:
+		c.millis, c.sequence = now, 0
+	case c.sequence < maxSequence:
+		c.sequence++
+	default:
+		c.millis, c.sequence = c.millis+1, 0
+	}
+
+	return c.enc.Encode(c.millis, uint16(c.sequence))
+}
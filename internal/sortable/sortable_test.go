@@ -0,0 +1,124 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package sortable
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sixafter/nanoid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoding_Width(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	tests := map[string]int{
+		"01":                   64,
+		"0123456789":           20,
+		"0123456789abcdef":     16,
+		nanoid.DefaultAlphabet: 11,
+	}
+
+	for alphabet, want := range tests {
+		enc, err := NewEncoding(alphabet)
+		is.NoError(err)
+		is.Equal(want, enc.Width(), alphabet)
+	}
+}
+
+func TestEncoding_RoundTrip(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for _, alphabet := range []string{"01", "0123456789", nanoid.DefaultAlphabet, "αβγδεζηθ"} {
+		enc, err := NewEncoding(alphabet)
+		is.NoError(err)
+
+		when := time.Date(2026, 10, 16, 12, 30, 45, 123_000_000, time.UTC)
+		lead := enc.Encode(when.UnixMilli(), 42)
+		is.Equal(enc.Width(), len([]rune(lead)))
+
+		stamp, err := enc.Decode(lead + "trailing body")
+		is.NoError(err)
+		is.True(when.Equal(stamp.Time), "%s: got %s", alphabet, stamp.Time)
+		is.Equal(uint16(42), stamp.Sequence)
+	}
+}
+
+func TestEncoding_Order(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// The default alphabet is not in code point order; the lead must sort anyway.
+	enc, err := NewEncoding(nanoid.DefaultAlphabet)
+	is.NoError(err)
+
+	values := []struct {
+		millis   int64
+		sequence uint16
+	}{
+		{0, 0}, {0, 1}, {0, maxSequence}, {1, 0}, {63, 5}, {64, 0},
+		{time.Now().UnixMilli(), 0}, {time.Now().UnixMilli() + 1, 0}, {maxMillis, maxSequence},
+	}
+
+	var leads []string
+	for _, v := range values {
+		leads = append(leads, enc.Encode(v.millis, v.sequence))
+	}
+	is.True(slices.IsSorted(leads), "Expected leads to sort in issue order: %v", leads)
+}
+
+func TestEncoding_DecodeInvalid(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	enc, err := NewEncoding("0123456789")
+	is.NoError(err)
+
+	_, err = enc.Decode("123")
+	is.ErrorIs(err, ErrInvalidLead)
+
+	_, err = enc.Decode("1234567890123456789x")
+	is.ErrorIs(err, ErrInvalidLead)
+
+	// 99999999999999999999 does not fit in 64 bits
+	_, err = enc.Decode("99999999999999999999")
+	is.ErrorIs(err, ErrInvalidLead)
+
+	_, err = NewEncoding("aa")
+	is.Error(err)
+}
+
+func TestClock_Monotonic(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	enc, err := NewEncoding("0123456789abcdef")
+	is.NoError(err)
+
+	frozen := time.UnixMilli(1_700_000_000_000)
+	clock := NewClock(enc)
+	clock.now = func() time.Time { return frozen }
+
+	// Exhaust the sequence space of one millisecond and step the clock backwards.
+	var leads []string
+	for i := range maxSequence + 10 {
+		if i == maxSequence+5 {
+			frozen = frozen.Add(-time.Second)
+		}
+		leads = append(leads, clock.Next())
+	}
+
+	is.True(slices.IsSorted(leads))
+	is.Len(slices.Compact(slices.Clone(leads)), len(leads), "Expected every lead to be distinct")
+
+	last, err := enc.Decode(leads[len(leads)-1])
+	is.NoError(err)
+	is.Equal(frozen.Add(time.Second).UnixMilli()+1, last.Time.UnixMilli(), "Expected the clock to borrow the next millisecond")
+}
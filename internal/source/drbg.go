@@ -0,0 +1,118 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package source
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sixafter/aes-ctr-drbg"
+)
+
+// maxReseedRequests is the NIST SP 800-90A maximum number of requests between reseeds (2^48).
+const maxReseedRequests = 1 << 48
+
+// ErrInvalidDRBGOptions is returned when DRBGOptions are inconsistent or out of range.
+var ErrInvalidDRBGOptions = errors.New("invalid AES-CTR-DRBG options")
+
+// DRBGOptions tunes the AES-CTR-DRBG source. The zero value selects the
+// ctrdrbg.DefaultConfig settings.
+type DRBGOptions struct {
+	// KeySize is the AES key size in bits: 128, 192, or 256. Zero selects AES-256.
+	KeySize int
+
+	// Personalization is mixed into the DRBG seed so that streams with different
+	// personalization strings are cryptographically separated. It may be at most
+	// the seed length (key size plus 16 bytes) long.
+	Personalization string
+
+	// PredictionResistance reseeds from system entropy before every read.
+	PredictionResistance bool
+
+	// ReseedInterval reseeds from system entropy once this much time has elapsed. Zero disables it.
+	ReseedInterval time.Duration
+
+	// ReseedRequests reseeds from system entropy after this many reads. Zero disables it.
+	ReseedRequests uint64
+
+	// MaxBytesPerKey enables key rotation after this many output bytes. Zero disables rotation.
+	MaxBytesPerKey uint64
+}
+
+// IsZero reports whether o leaves every setting at its default.
+func (o DRBGOptions) IsZero() bool {
+	return o == DRBGOptions{}
+}
+
+// Validate reports whether the options are in range and consistent with each other.
+func (o DRBGOptions) Validate() error {
+	keyBytes := ctrdrbg.KeySize256
+	switch o.KeySize {
+	case 0, 256:
+	case 128:
+		keyBytes = ctrdrbg.KeySize128
+	case 192:
+		keyBytes = ctrdrbg.KeySize192
+	default:
+		return fmt.Errorf("%w: key size must be 128, 192, or 256 bits, got %d", ErrInvalidDRBGOptions, o.KeySize)
+	}
+
+	if seedLen := int(keyBytes) + 16; len(o.Personalization) > seedLen {
+		return fmt.Errorf("%w: personalization must be at most %d bytes for a %d-bit key, got %d",
+			ErrInvalidDRBGOptions, seedLen, int(keyBytes)*8, len(o.Personalization))
+	}
+
+	if o.ReseedInterval < 0 {
+		return fmt.Errorf("%w: reseed interval must not be negative", ErrInvalidDRBGOptions)
+	}
+
+	if o.ReseedRequests > maxReseedRequests {
+		return fmt.Errorf("%w: reseed requests must be at most 2^48", ErrInvalidDRBGOptions)
+	}
+
+	if o.PredictionResistance && (o.ReseedInterval > 0 || o.ReseedRequests > 0) {
+		return fmt.Errorf("%w: prediction resistance already reseeds before every read; "+
+			"it cannot be combined with a reseed interval or reseed request limit", ErrInvalidDRBGOptions)
+	}
+
+	return nil
+}
+
+// options converts o into the equivalent ctrdrbg functional options.
+func (o DRBGOptions) options() []ctrdrbg.Option {
+	var opts []ctrdrbg.Option
+
+	switch o.KeySize {
+	case 128:
+		opts = append(opts, ctrdrbg.WithKeySize(ctrdrbg.KeySize128))
+	case 192:
+		opts = append(opts, ctrdrbg.WithKeySize(ctrdrbg.KeySize192))
+	case 256:
+		opts = append(opts, ctrdrbg.WithKeySize(ctrdrbg.KeySize256))
+	}
+
+	if o.Personalization != "" {
+		opts = append(opts, ctrdrbg.WithPersonalization([]byte(o.Personalization)))
+	}
+	if o.PredictionResistance {
+		opts = append(opts, ctrdrbg.WithPredictionResistance(true))
+	}
+	if o.ReseedInterval > 0 {
+		opts = append(opts, ctrdrbg.WithReseedInterval(o.ReseedInterval))
+	}
+	if o.ReseedRequests > 0 {
+		opts = append(opts, ctrdrbg.WithReseedRequests(o.ReseedRequests))
+	}
+	if o.MaxBytesPerKey > 0 {
+		opts = append(opts,
+			ctrdrbg.WithEnableKeyRotation(true),
+			ctrdrbg.WithMaxBytesPerKey(o.MaxBytesPerKey),
+		)
+	}
+
+	return opts
+}
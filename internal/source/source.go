@@ -0,0 +1,124 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package source constructs the random sources available to the Nano ID generator.
+package source
+
+import (
+	"crypto/fips140"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sixafter/aes-ctr-drbg"
+	"github.com/sixafter/prng-chacha"
+)
+
+// Names of the supported random sources.
+const (
+	// Auto selects CTRDRBG when FIPS 140 mode is enabled and ChaCha20 otherwise,
+	// mirroring nanoid.WithAutoRandReader.
+	Auto = "auto"
+
+	// CryptoRand reads directly from the operating system via crypto/rand.
+	CryptoRand = "crypto-rand"
+
+	// ChaCha20 uses the pooled ChaCha20 CSPRNG from github.com/sixafter/prng-chacha.
+	ChaCha20 = "chacha20"
+
+	// CTRDRBG uses the NIST SP 800-90A AES-CTR-DRBG from github.com/sixafter/aes-ctr-drbg.
+	CTRDRBG = "ctr-drbg"
+)
+
+// Names lists the supported random sources in the order they are documented.
+var Names = []string{Auto, CryptoRand, ChaCha20, CTRDRBG}
+
+var (
+	// ErrUnknownSource is returned for a source name that is not one of Names.
+	ErrUnknownSource = errors.New("unknown random source")
+
+	// ErrNotFIPSApproved is returned when a source that is not FIPS 140 approved is
+	// requested while FIPS 140 mode is enabled.
+	ErrNotFIPSApproved = errors.New("random source is not permitted in FIPS 140 mode")
+)
+
+// config holds the settings applied by Option values.
+type config struct {
+	drbg DRBGOptions
+}
+
+// Option customizes the source returned by New.
+type Option func(*config)
+
+// WithDRBG tunes the AES-CTR-DRBG source. New rejects non-default options for any
+// other source.
+func WithDRBG(o DRBGOptions) Option {
+	return func(c *config) {
+		c.drbg = o
+	}
+}
+
+// Resolve returns the concrete source selected by name, replacing Auto with the
+// source chosen for the current FIPS 140 mode.
+func Resolve(name string) (string, error) {
+	switch name {
+	case Auto:
+		if fips140.Enabled() {
+			return CTRDRBG, nil
+		}
+		return ChaCha20, nil
+	case CryptoRand, CTRDRBG:
+		return name, nil
+	case ChaCha20:
+		if fips140.Enabled() {
+			return "", fmt.Errorf("%w: %s", ErrNotFIPSApproved, name)
+		}
+		return name, nil
+	default:
+		return "", fmt.Errorf("%w %q; must be one of: %s", ErrUnknownSource, name, strings.Join(Names, ", "))
+	}
+}
+
+// New returns a new reader for the named source together with the resolved source name.
+//
+// ChaCha20 and CTRDRBG readers are built with their package's NewReader and are
+// sharded internally, so a single reader may be shared by concurrent generators.
+func New(name string, opts ...Option) (io.Reader, string, error) {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	resolved, err := Resolve(name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !cfg.drbg.IsZero() {
+		if resolved != CTRDRBG {
+			return nil, "", fmt.Errorf("%w: DRBG options require the %s source, got %s", ErrInvalidDRBGOptions, CTRDRBG, resolved)
+		}
+		if err = cfg.drbg.Validate(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var reader io.Reader
+	switch resolved {
+	case CryptoRand:
+		reader = rand.Reader
+	case ChaCha20:
+		reader, err = prng.NewReader()
+	case CTRDRBG:
+		reader, err = ctrdrbg.NewReader(cfg.drbg.options()...)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to initialize %s random source: %w", resolved, err)
+	}
+
+	return reader, resolved, nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package source
+
+import (
+	"bytes"
+	"crypto/fips140"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve_Auto(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	name, err := Resolve(Auto)
+	is.NoError(err)
+	if fips140.Enabled() {
+		is.Equal(CTRDRBG, name)
+	} else {
+		is.Equal(ChaCha20, name)
+	}
+}
+
+func TestResolve_Unknown(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	_, err := Resolve("dev-random")
+	is.ErrorIs(err, ErrUnknownSource)
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{CryptoRand, ChaCha20, CTRDRBG} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := assert.New(t)
+
+			if name == ChaCha20 && fips140.Enabled() {
+				_, _, err := New(name)
+				is.ErrorIs(err, ErrNotFIPSApproved)
+				return
+			}
+
+			reader, resolved, err := New(name)
+			is.NoError(err)
+			is.Equal(name, resolved)
+
+			buf := make([]byte, 64)
+			n, err := reader.Read(buf)
+			is.NoError(err)
+			is.Equal(len(buf), n)
+			is.False(bytes.Equal(buf, make([]byte, len(buf))), "Expected random output")
+		})
+	}
+}
+
+func TestNew_DRBGOptions(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	reader, resolved, err := New(CTRDRBG, WithDRBG(DRBGOptions{
+		KeySize:         128,
+		Personalization: "tenant-a",
+		ReseedRequests:  1000,
+	}))
+	is.NoError(err)
+	is.Equal(CTRDRBG, resolved)
+
+	buf := make([]byte, 32)
+	_, err = reader.Read(buf)
+	is.NoError(err)
+}
+
+func TestNew_InvalidDRBGOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		name string
+		opts DRBGOptions
+	}{
+		"wrong source":      {name: CryptoRand, opts: DRBGOptions{KeySize: 128}},
+		"key size":          {name: CTRDRBG, opts: DRBGOptions{KeySize: 512}},
+		"personalization":   {name: CTRDRBG, opts: DRBGOptions{KeySize: 128, Personalization: strings.Repeat("x", 33)}},
+		"negative interval": {name: CTRDRBG, opts: DRBGOptions{ReseedInterval: -time.Second}},
+		"too many requests": {name: CTRDRBG, opts: DRBGOptions{ReseedRequests: 1<<48 + 1}},
+		"prediction reseed": {name: CTRDRBG, opts: DRBGOptions{PredictionResistance: true, ReseedRequests: 10}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := assert.New(t)
+
+			_, _, err := New(tc.name, WithDRBG(tc.opts))
+			is.ErrorIs(err, ErrInvalidDRBGOptions)
+		})
+	}
+}
+
+func TestNewSeeded(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	if fips140.Enabled() {
+		_, err := NewSeeded("fixtures")
+		is.ErrorIs(err, ErrNotFIPSApproved)
+		return
+	}
+
+	// A 64-digit hex seed is the key itself: RFC 8439 A.1 test vector #1.
+	reader, err := NewSeeded(strings.Repeat("0", 64))
+	is.NoError(err)
+
+	buf := make([]byte, 16)
+	_, err = reader.Read(buf)
+	is.NoError(err)
+	is.Equal("76b8e0ada0f13d90405d6ae55386bd28", hex.EncodeToString(buf))
+
+	// Passphrases are reproducible and distinct from one another.
+	read := func(seed string) []byte {
+		r, err := NewSeeded(seed)
+		is.NoError(err)
+		b := make([]byte, 64)
+		_, err = io.ReadFull(r, b)
+		is.NoError(err)
+		return b
+	}
+	is.Equal(read("fixtures"), read("fixtures"))
+	is.NotEqual(read("fixtures"), read("fixtures2"))
+
+	_, err = NewSeeded("")
+	is.ErrorIs(err, ErrInvalidSeed)
+}
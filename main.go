@@ -6,10 +6,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/sixafter/nanoid-cli/cmd"
+	"github.com/sixafter/nanoid-cli/cmd/validate"
+	"github.com/sixafter/nanoid-cli/internal/interrupt"
 )
 
 // run is the main entry point for the CLI, allowing it to be tested without os.Exit.
@@ -17,9 +21,17 @@ func run() error {
 	return cmd.Execute()
 }
 
+// report writes err to w, unless it is a quiet validation failure that only the
+// exit status reports, and returns the exit status for it.
+func report(w io.Writer, err error) int {
+	if !errors.Is(err, validate.ErrValidationFailed) {
+		_, _ = fmt.Fprintf(w, "Error: %v\n", err)
+	}
+	return interrupt.ExitCode(err)
+}
+
 func main() {
 	if err := run(); err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(report(os.Stderr, err))
 	}
 }
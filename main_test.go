@@ -7,11 +7,14 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/sixafter/nanoid-cli/cmd"
+	"github.com/sixafter/nanoid-cli/internal/interrupt"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -78,3 +81,95 @@ func TestRun_InvalidCommand(t *testing.T) {
 	output := outBuf.String()
 	is.Contains(output, "unknown command", "Expected unknown command error")
 }
+
+func TestRun_Timeout(t *testing.T) {
+	//t.Parallel()
+	is := assert.New(t)
+	t.Cleanup(func() { _ = cmd.RootCmd.PersistentFlags().Set("timeout", "0") })
+
+	// Set command-line arguments to a rate-limited batch that cannot finish in time
+	os.Args = []string{"nanoid", "generate", "--count", "1000", "--rate", "20/s", "--burst", "1", "--timeout", "200ms"}
+
+	// Capture output
+	var outBuf, errBuf bytes.Buffer
+	cmd.RootCmd.SetOut(&outBuf)
+	cmd.RootCmd.SetErr(&errBuf)
+
+	// Execute Run and check that the batch was cut short by the timeout
+	err := run()
+	is.ErrorIs(err, interrupt.ErrTimeout)
+	is.Equal(interrupt.ExitTimeout, interrupt.ExitCode(err))
+
+	// Every ID written before the timeout is complete
+	ids := strings.Split(strings.TrimSpace(outBuf.String()), "\n")
+	is.NotEmpty(ids)
+	is.Less(len(ids), 1000)
+	for _, id := range ids {
+		is.Len(id, 21)
+	}
+}
+
+func TestRun_TimeoutNotReached(t *testing.T) {
+	//t.Parallel()
+	is := assert.New(t)
+	t.Cleanup(func() { _ = cmd.RootCmd.PersistentFlags().Set("timeout", "0") })
+
+	// A command that is killed when it times out must not be killed when it returns in time
+	os.Args = []string{"nanoid", "alphabets", "list", "--timeout", "1h"}
+
+	// Capture output
+	var outBuf bytes.Buffer
+	cmd.RootCmd.SetOut(&outBuf)
+	cmd.RootCmd.SetErr(&outBuf)
+
+	// Execute Run and check that the process survives the cancelled context
+	is.NoError(run())
+	time.Sleep(50 * time.Millisecond)
+	is.Contains(outBuf.String(), "no-lookalikes")
+}
+
+func TestRun_Interruptible(t *testing.T) {
+	//t.Parallel()
+	is := assert.New(t)
+
+	// Run once so that the subcommands are registered
+	os.Args = []string{"nanoid", "version"}
+	var outBuf bytes.Buffer
+	cmd.RootCmd.SetOut(&outBuf)
+	cmd.RootCmd.SetErr(&outBuf)
+	is.NoError(run())
+
+	// Only commands that stop once their context is done may catch SIGINT and SIGTERM
+	want := map[string]bool{"generate": true, "validate": true, "analyze": true, "bench": true, "serve": true}
+	for _, c := range cmd.RootCmd.Commands() {
+		_, ok := c.Annotations[interrupt.Annotation]
+		is.Equal(want[c.Name()], ok, "Unexpected interrupt handling for %s", c.Name())
+	}
+}
+
+func TestRun_ValidateQuiet(t *testing.T) {
+	//t.Parallel()
+	is := assert.New(t)
+
+	// Set command-line arguments to a quiet validation with a failing ID
+	os.Args = []string{"nanoid", "validate", "--quiet", "--alphabet", "abc", "--id-length", "3", "abc", "xyz"}
+
+	// Capture output
+	var outBuf, errBuf bytes.Buffer
+	cmd.RootCmd.SetOut(&outBuf)
+	cmd.RootCmd.SetErr(&errBuf)
+
+	// The failure is reported through the exit status alone
+	err := run()
+	is.Error(err)
+
+	var reported bytes.Buffer
+	is.Equal(interrupt.ExitFailure, report(&reported, err))
+	is.Empty(reported.String(), "Expected main to print nothing under --quiet")
+	is.Empty(outBuf.String())
+	is.Empty(errBuf.String())
+
+	// Other errors are still printed
+	is.Equal(interrupt.ExitFailure, report(&reported, errors.New("boom")))
+	is.Equal("Error: boom\n", reported.String())
+}
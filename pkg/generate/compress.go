@@ -0,0 +1,210 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package generate
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Supported output compression formats.
+const (
+	// CompressGzip writes a gzip (RFC 1952) stream.
+	CompressGzip = "gzip"
+
+	// CompressZlib writes a zlib (RFC 1950) stream.
+	CompressZlib = "zlib"
+)
+
+// compressions lists the supported compression formats in the order they are documented.
+var compressions = []string{CompressGzip, CompressZlib}
+
+// Compressions returns the names of the supported compression formats.
+func Compressions() []string {
+	return slices.Clone(compressions)
+}
+
+// compressorQueue is the number of chunks that may wait for the compressing goroutine
+// before writers block.
+const compressorQueue = 64
+
+// errCompressorClosed is returned by writes to a closed compressor.
+var errCompressorClosed = errors.New("compressor closed")
+
+// encoder is the interface shared by the gzip and zlib writers.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// compressionLevel returns the DEFLATE level suited to an output format. Random IDs
+// contain no repeated strings, so formats that are little more than bare IDs gain
+// only from Huffman coding, and the LZ77 levels then fall back to storing them
+// uncompressed. Formats that repeat field names and values per ID use the fastest
+// LZ77 level, as higher levels save little more on them.
+func compressionLevel(format string) int {
+	switch format {
+	case FormatText, FormatYAML:
+		return gzip.HuffmanOnly
+	default:
+		return gzip.BestSpeed
+	}
+}
+
+// newEncoder returns a writer that compresses to w in the named format at the given level.
+func newEncoder(name string, level int, w io.Writer) (encoder, error) {
+	switch name {
+	case CompressGzip:
+		return gzip.NewWriterLevel(w, level)
+	case CompressZlib:
+		return zlib.NewWriterLevel(w, level)
+	default:
+		return nil, fmt.Errorf("unsupported compression %q; must be one of: %s", name, strings.Join(compressions, ", "))
+	}
+}
+
+// compressorOp is a chunk of data, or a flush or close request, for the compressing goroutine.
+type compressorOp struct {
+	data  *[]byte
+	close bool
+
+	// ack, if set, receives the result of a flush or close once it has completed.
+	ack chan error
+}
+
+// compressor compresses everything written to it on a separate goroutine, so that
+// compression does not throttle the goroutine producing the output. Writes copy
+// their data and return immediately unless compressorQueue chunks are pending.
+//
+// A compressor is used by a single writing goroutine. Errors from the underlying
+// writer are reported by a later Write, Flush, or Close.
+type compressor struct {
+	ops  chan compressorOp
+	done chan struct{}
+	pool sync.Pool
+
+	mu     sync.Mutex
+	err    error
+	closed bool
+}
+
+// newCompressor starts a compressor writing the named format to w at the given level.
+func newCompressor(name string, level int, w io.Writer) (*compressor, error) {
+	enc, err := newEncoder(name, level, w)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &compressor{ops: make(chan compressorOp, compressorQueue), done: make(chan struct{})}
+	go c.run(enc)
+	return c, nil
+}
+
+// run compresses chunks until a close request is received.
+func (c *compressor) run(enc encoder) {
+	defer close(c.done)
+
+	for op := range c.ops {
+		if op.data != nil {
+			if c.failed() == nil {
+				if _, err := enc.Write(*op.data); err != nil {
+					c.fail(err)
+				}
+			}
+			c.pool.Put(op.data)
+			continue
+		}
+
+		if c.failed() == nil {
+			var err error
+			if op.close {
+				err = enc.Close()
+			} else {
+				err = enc.Flush()
+			}
+			if err != nil {
+				c.fail(err)
+			}
+		}
+		op.ack <- c.failed()
+		if op.close {
+			return
+		}
+	}
+}
+
+// fail records the first error from the underlying writer.
+func (c *compressor) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// failed returns the first error from the underlying writer, if any.
+func (c *compressor) failed() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Write queues a copy of p for compression.
+func (c *compressor) Write(p []byte) (int, error) {
+	if c.closed {
+		return 0, errCompressorClosed
+	}
+	if err := c.failed(); err != nil {
+		return 0, err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	// The pool holds pointers, so that returning a buffer to it does not allocate
+	buf, _ := c.pool.Get().(*[]byte)
+	if buf == nil {
+		buf = new([]byte)
+	}
+	*buf = append((*buf)[:0], p...)
+	c.ops <- compressorOp{data: buf}
+	return len(p), nil
+}
+
+// Flush waits until everything written so far has been compressed and flushed to
+// the underlying writer.
+func (c *compressor) Flush() error {
+	if c.closed {
+		return errCompressorClosed
+	}
+	return c.request(false)
+}
+
+// Close compresses everything written so far, writes the stream trailer, and stops
+// the compressing goroutine. Closing a closed compressor does nothing.
+func (c *compressor) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	err := c.request(true)
+	<-c.done
+	return err
+}
+
+// request sends a flush or close request and waits for its result.
+func (c *compressor) request(closing bool) error {
+	ack := make(chan error, 1)
+	c.ops <- compressorOp{close: closing, ack: ack}
+	return <-ack
+}
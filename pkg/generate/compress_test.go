@@ -0,0 +1,105 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package generate
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// syncBuffer is a bytes.Buffer that may be read while the compressor writes to it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return bytes.Clone(b.buf.Bytes())
+}
+
+func TestCompressor_RoundTrip(t *testing.T) {
+	is := assert.New(t)
+
+	readers := map[string]func(io.Reader) (io.Reader, error){
+		CompressGzip: func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		CompressZlib: func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) },
+	}
+
+	input := strings.Repeat("V1StGXR8_Z5jdHi6B-myT\n", 10000)
+	for name, newReader := range readers {
+		var out syncBuffer
+		c, err := newCompressor(name, compressionLevel(FormatText), &out)
+		is.NoError(err, name)
+
+		// Write in uneven chunks, reusing the buffer to check that writes are copied.
+		chunk := make([]byte, 0, 1000)
+		for data := []byte(input); len(data) > 0; {
+			n := min(len(data), 777)
+			chunk = append(chunk[:0], data[:n]...)
+			_, err := c.Write(chunk)
+			is.NoError(err)
+			clear(chunk)
+			data = data[n:]
+		}
+
+		// A flush makes everything written so far decodable before the stream ends.
+		is.NoError(c.Flush())
+		r, err := newReader(bytes.NewReader(out.Bytes()))
+		is.NoError(err, name)
+		partial, _ := io.ReadAll(r)
+		is.Equal(input, string(partial), name)
+
+		is.NoError(c.Close())
+		is.NoError(c.Close(), "Expected a second Close to do nothing")
+		_, err = c.Write([]byte("x"))
+		is.ErrorIs(err, errCompressorClosed)
+
+		r, err = newReader(bytes.NewReader(out.Bytes()))
+		is.NoError(err, name)
+		all, err := io.ReadAll(r)
+		is.NoError(err, name)
+		is.Equal(input, string(all), name)
+	}
+
+	_, err := newCompressor("zstd", gzip.BestSpeed, io.Discard)
+	is.Error(err)
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+var errDiskFull = errors.New("disk full")
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errDiskFull }
+
+func TestCompressor_Error(t *testing.T) {
+	is := assert.New(t)
+
+	c, err := newCompressor(CompressGzip, gzip.BestSpeed, failingWriter{})
+	is.NoError(err)
+
+	_, _ = c.Write([]byte("some output"))
+	is.ErrorIs(c.Flush(), errDiskFull)
+	_, err = c.Write([]byte("more"))
+	is.ErrorIs(err, errDiskFull, "Expected later writes to report the failure")
+	is.ErrorIs(c.Close(), errDiskFull)
+}
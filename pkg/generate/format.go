@@ -0,0 +1,233 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package generate
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sixafter/nanoid"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported output formats for generated IDs.
+const (
+	// FormatText writes one bare ID per line.
+	FormatText = "text"
+
+	// FormatJSON writes a single JSON array of IDs.
+	FormatJSON = "json"
+
+	// FormatNDJSON writes one JSON object per line describing each ID.
+	FormatNDJSON = "ndjson"
+
+	// FormatCSV writes a CSV document with a header row describing each ID.
+	FormatCSV = "csv"
+
+	// FormatYAML writes a single YAML sequence of IDs.
+	FormatYAML = "yaml"
+)
+
+// formats lists the supported output formats in the order they are documented.
+var formats = []string{FormatText, FormatJSON, FormatNDJSON, FormatCSV, FormatYAML}
+
+// Formats returns the names of the supported output formats.
+func Formats() []string {
+	return slices.Clone(formats)
+}
+
+// formatter encodes a stream of generated IDs in a particular output format.
+//
+// Every method is called from the single goroutine that owns the output writer;
+// begin is called before the first ID and end after the last of each output file;
+// a split output begins and ends the document again for every file.
+type formatter interface {
+	begin(w io.Writer) error
+	write(w io.Writer, index int, id nanoid.ID) error
+	end(w io.Writer) error
+}
+
+// flusher is implemented by formatters that buffer output internally and must be
+// flushed before the underlying writer for the output to reach its destination.
+type flusher interface {
+	flush() error
+}
+
+// record describes a single ID in the structured (NDJSON and CSV) output formats.
+type record struct {
+	// ID is the generated identifier, encoded via its MarshalText implementation.
+	ID *nanoid.ID `json:"id"`
+
+	// Length is the number of characters (runes) in the ID.
+	Length int `json:"length"`
+
+	// AlphabetHash identifies the alphabet the ID was drawn from.
+	AlphabetHash string `json:"alphabet_hash"`
+
+	// Index is the zero-based position of the ID in the output.
+	Index int `json:"index"`
+}
+
+// newFormatter returns the formatter for the named format.
+func newFormatter(name, alphabet string) (formatter, error) {
+	hash := alphabetHash(alphabet)
+
+	switch name {
+	case FormatText:
+		return &textFormatter{}, nil
+	case FormatJSON:
+		return &jsonFormatter{}, nil
+	case FormatNDJSON:
+		return &ndjsonFormatter{alphabetHash: hash}, nil
+	case FormatCSV:
+		return &csvFormatter{alphabetHash: hash}, nil
+	case FormatYAML:
+		return &yamlFormatter{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q; must be one of: %s", name, strings.Join(formats, ", "))
+	}
+}
+
+// alphabetHash returns a short, stable fingerprint of the alphabet: the first
+// 8 bytes of its SHA-256 digest, hex encoded.
+func alphabetHash(alphabet string) string {
+	sum := sha256.Sum256([]byte(alphabet))
+	return hex.EncodeToString(sum[:8])
+}
+
+// textFormatter writes one bare ID per line.
+type textFormatter struct{}
+
+func (f *textFormatter) begin(io.Writer) error { return nil }
+
+func (f *textFormatter) write(w io.Writer, _ int, id nanoid.ID) error {
+	text, err := id.MarshalText()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(text, '\n'))
+	return err
+}
+
+func (f *textFormatter) end(io.Writer) error { return nil }
+
+// jsonFormatter writes a JSON array with one ID per line.
+type jsonFormatter struct {
+	// written is the number of IDs in the current array, which begin resets so
+	// that every file of a split output is a complete document.
+	written int
+}
+
+func (f *jsonFormatter) begin(w io.Writer) error {
+	f.written = 0
+	_, err := io.WriteString(w, "[\n")
+	return err
+}
+
+func (f *jsonFormatter) write(w io.Writer, _ int, id nanoid.ID) error {
+	b, err := json.Marshal(&id)
+	if err != nil {
+		return err
+	}
+
+	sep := "  "
+	if f.written > 0 {
+		sep = ",\n  "
+	}
+	if _, err = io.WriteString(w, sep); err != nil {
+		return err
+	}
+	f.written++
+	_, err = w.Write(b)
+	return err
+}
+
+func (f *jsonFormatter) end(w io.Writer) error {
+	_, err := io.WriteString(w, "\n]\n")
+	return err
+}
+
+// ndjsonFormatter writes one JSON object per line.
+type ndjsonFormatter struct {
+	alphabetHash string
+	encoder      *json.Encoder
+}
+
+func (f *ndjsonFormatter) begin(w io.Writer) error {
+	f.encoder = json.NewEncoder(w)
+	return nil
+}
+
+func (f *ndjsonFormatter) write(_ io.Writer, index int, id nanoid.ID) error {
+	return f.encoder.Encode(record{
+		ID:           &id,
+		Length:       utf8.RuneCountInString(string(id)),
+		AlphabetHash: f.alphabetHash,
+		Index:        index,
+	})
+}
+
+func (f *ndjsonFormatter) end(io.Writer) error { return nil }
+
+// csvFormatter writes a CSV document with a header row.
+type csvFormatter struct {
+	alphabetHash string
+	writer       *csv.Writer
+}
+
+func (f *csvFormatter) begin(w io.Writer) error {
+	f.writer = csv.NewWriter(w)
+	return f.writer.Write([]string{"id", "length", "alphabet_hash", "index"})
+}
+
+func (f *csvFormatter) write(_ io.Writer, index int, id nanoid.ID) error {
+	text, err := id.MarshalText()
+	if err != nil {
+		return err
+	}
+
+	return f.writer.Write([]string{
+		string(text),
+		strconv.Itoa(utf8.RuneCount(text)),
+		f.alphabetHash,
+		strconv.Itoa(index),
+	})
+}
+
+func (f *csvFormatter) end(io.Writer) error {
+	return f.flush()
+}
+
+func (f *csvFormatter) flush() error {
+	f.writer.Flush()
+	return f.writer.Error()
+}
+
+// yamlFormatter writes a YAML sequence with one ID per item.
+type yamlFormatter struct{}
+
+func (f *yamlFormatter) begin(io.Writer) error { return nil }
+
+func (f *yamlFormatter) write(w io.Writer, _ int, id nanoid.ID) error {
+	// Marshal each ID as a single-item sequence so that yaml.v3 applies its
+	// quoting rules; concatenated items form one sequence document.
+	b, err := yaml.Marshal([]*nanoid.ID{&id})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func (f *yamlFormatter) end(io.Writer) error { return nil }
@@ -0,0 +1,767 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package generate generates batches or unbounded streams of Nano IDs across
+// concurrent workers and writes them, optionally rate limited, deduplicated,
+// compressed, and split across files, in one of several output formats. It is
+// the engine behind "nanoid generate" and can be embedded in other programs:
+//
+//	stats, err := generate.Run(ctx, generate.Options{
+//		Preset: "base58",
+//		Length: 16,
+//		Count:  1000,
+//		Format: generate.FormatNDJSON,
+//	}, os.Stdout)
+//
+// A Run writes a batch of Count IDs, or, with Stream set, generates IDs until its
+// context is done or its output is closed.
+//
+// Every call to Run owns its generators, output, and statistics, so Run is safe
+// to call from several goroutines at once.
+package generate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sixafter/nanoid"
+	"github.com/sixafter/nanoid-cli/internal/alphabet"
+	"github.com/sixafter/nanoid-cli/internal/checksum"
+	"github.com/sixafter/nanoid-cli/internal/dedup"
+	"github.com/sixafter/nanoid-cli/internal/entropy"
+	"github.com/sixafter/nanoid-cli/internal/sortable"
+	"github.com/sixafter/nanoid-cli/internal/source"
+)
+
+const (
+	// DefaultFlushInterval is how often output is flushed while streaming or rate
+	// limiting when Options.FlushInterval is zero.
+	DefaultFlushInterval = 100 * time.Millisecond
+
+	// DefaultUniqueMemory is the number of IDs tracked exactly in memory under
+	// Options.Unique when Options.UniqueMemory is zero.
+	DefaultUniqueMemory = dedup.DefaultMemoryLimit
+
+	// DefaultProgressInterval is how often Options.Progress is called when
+	// Options.ProgressInterval is zero.
+	DefaultProgressInterval = 250 * time.Millisecond
+)
+
+// DRBGOptions tunes the AES-CTR-DRBG random source; the zero value uses its defaults.
+type DRBGOptions = source.DRBGOptions
+
+// Options configures a Run. The zero value of every field selects its default.
+type Options struct {
+	// Length is the number of random characters in each ID. Zero uses nanoid.DefaultLength.
+	Length int
+
+	// Alphabet is the set of characters IDs are drawn from. Empty uses nanoid.DefaultAlphabet.
+	Alphabet string
+
+	// Preset names a built-in alphabet, such as "base58", or an alphabet expression,
+	// such as "a-z,0-9,-lookalikes", used instead of Alphabet.
+	Preset string
+
+	// Prefix is written verbatim before every ID, such as "usr_".
+	Prefix string
+
+	// Sortable starts every ID with a millisecond timestamp and sequence number so
+	// that IDs sort in the order they were issued.
+	Sortable bool
+
+	// Checksum names the scheme used to append check characters to every ID, if any:
+	// "luhn" or "weighted".
+	Checksum string
+
+	// Count is the number of IDs in a batch. Zero generates a single ID, unless
+	// Stream is set.
+	Count int
+
+	// Stream generates IDs until the context is done or the output is closed,
+	// instead of a batch. Count must then be zero.
+	Stream bool
+
+	// Workers is the number of goroutines generating IDs. Zero uses GOMAXPROCS.
+	Workers int
+
+	// Format names the output format, one of Formats. Empty uses FormatText.
+	Format string
+
+	// Source names the random source: "auto", "crypto-rand", "chacha20", or
+	// "ctr-drbg". Empty uses "auto".
+	Source string
+
+	// DRBG tunes the AES-CTR-DRBG, and may only be set when Source resolves to it.
+	DRBG DRBGOptions
+
+	// Seed replaces the random source with a ChaCha20 stream keyed by it (64 hex
+	// digits, or any passphrase), so that the same seed and options always produce
+	// the same IDs. Seeded IDs are predictable and NOT secure; they are generated
+	// by a single worker and refused in FIPS 140 mode.
+	Seed string
+
+	// Rate limits output to this many IDs per second. Zero is unlimited.
+	Rate float64
+
+	// Burst is the number of IDs that may be written back-to-back under Rate. Zero
+	// allows a tenth of a second's worth.
+	Burst int
+
+	// FlushInterval is how often output is flushed while streaming or rate limiting.
+	// Zero uses DefaultFlushInterval.
+	FlushInterval time.Duration
+
+	// Unique regenerates duplicate IDs so that the batch contains none. It cannot be
+	// combined with Stream, and Count must leave enough of the keyspace unused for
+	// the last IDs to be found in a bounded number of attempts.
+	Unique bool
+
+	// UniqueMemory is the number of IDs tracked exactly in memory under Unique;
+	// beyond it a Bloom filter is used. Zero uses DefaultUniqueMemory.
+	UniqueMemory int
+
+	// UniqueSpillDir, if set, is where sorted runs of tracked IDs are spilled once
+	// UniqueMemory is exceeded, instead of using a Bloom filter.
+	UniqueSpillDir string
+
+	// Output names the file IDs are written to instead of the writer passed to Run.
+	// Files are written under a temporary name and renamed into place once complete.
+	Output string
+
+	// SplitLines starts a new Output file, numbered such as ids-00002.txt, after
+	// this many IDs.
+	SplitLines int
+
+	// SplitSize starts a new Output file once it reaches this many bytes, counted
+	// before compression.
+	SplitSize uint64
+
+	// Manifest writes a sidecar, such as ids.manifest.json, listing every Output file.
+	Manifest bool
+
+	// Force allows Output files to replace existing ones. When splitting, a completed
+	// run also removes the higher-numbered files an earlier run left behind.
+	Force bool
+
+	// Compression names the format output is compressed with, one of Compressions,
+	// or is empty for none.
+	Compression string
+
+	// Progress, if set, is called with a snapshot of the run every ProgressInterval
+	// while IDs are written, and once more when the run ends. It is called from the
+	// goroutine that writes the output, never concurrently, so it should return quickly.
+	Progress func(Progress)
+
+	// ProgressInterval is how often Progress is called. Zero or less uses DefaultProgressInterval.
+	ProgressInterval time.Duration
+}
+
+// Progress is a snapshot of a Run, passed to Options.Progress.
+type Progress struct {
+	// IDs is the number of IDs written so far.
+	IDs int
+
+	// Count is the number of IDs the run writes in total, or zero for a stream.
+	Count int
+
+	// Bytes is the size of the output written so far, before any compression.
+	Bytes int64
+
+	// Elapsed is the time since generation started.
+	Elapsed time.Duration
+}
+
+// Rate returns the average number of IDs written per second so far.
+func (p Progress) Rate() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.IDs) / p.Elapsed.Seconds()
+}
+
+// Remaining estimates the time left at the average rate so far. It returns zero
+// for a stream, or before any ID has been written.
+func (p Progress) Remaining() time.Duration {
+	rate := p.Rate()
+	if p.Count == 0 || rate == 0 {
+		return 0
+	}
+	return time.Duration(float64(p.Count-p.IDs) / rate * float64(time.Second))
+}
+
+// Stats describes the IDs written by a Run.
+type Stats struct {
+	// Start is when generation started.
+	Start time.Time
+
+	// Duration is how long generation took.
+	Duration time.Duration
+
+	// IDs is the number of IDs written.
+	IDs int
+
+	// Source is the name of the random source used, such as "chacha20" or "seeded".
+	Source string
+
+	// AlphabetSize is the number of characters the random part of each ID is drawn from.
+	AlphabetSize int
+
+	// Length is the number of random characters in each ID.
+	Length int
+
+	// Workers records the work of each generation worker.
+	Workers []WorkerStats
+
+	// Bytes is the size of the output before any compression.
+	Bytes int64
+
+	// CompressedBytes is the size of the output after compression, or zero if it
+	// was not compressed.
+	CompressedBytes int64
+
+	// TimestampWidth is the number of timestamp characters that lead each sortable ID.
+	TimestampWidth int
+
+	// Checksum is the scheme of the check characters appended to each ID, if any.
+	Checksum string
+
+	// ChecksumSize is the number of check characters appended to each ID.
+	ChecksumSize int
+
+	// Collisions is the number of duplicate IDs regenerated under Unique.
+	Collisions int
+
+	// UniqueMode is how emitted IDs were tracked under Unique: "exact", "bloom", or "spill".
+	UniqueMode string
+
+	// Files lists the Output files that were published.
+	Files []ManifestFile
+}
+
+// Entropy returns the estimated entropy of each ID in bits. Timestamp and check
+// characters are not random, so they are excluded.
+func (s Stats) Entropy() float64 {
+	return entropy.Bits(s.AlphabetSize, s.Length)
+}
+
+// OptionError reports an invalid Options field before any ID is written.
+type OptionError struct {
+	// Option is the name of the generate flag that sets the field, such as "id-length".
+	Option string
+
+	// Reason describes what is wrong with the field when there is no underlying error.
+	Reason string
+
+	// Err is the error the field caused, if any.
+	Err error
+}
+
+// Error returns a message naming the option, such as "id-length must be a positive integer".
+func (e *OptionError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("invalid %s: %v", e.Option, e.Err)
+	}
+	return e.Option + " " + e.Reason
+}
+
+// Unwrap returns the underlying error, if any.
+func (e *OptionError) Unwrap() error {
+	return e.Err
+}
+
+// normalize checks opts and returns a copy with defaults filled in and any preset expanded.
+func (o Options) normalize() (Options, error) {
+	invalid := func(option, reason string) (Options, error) {
+		return o, &OptionError{Option: option, Reason: reason}
+	}
+
+	switch {
+	case o.Length < 0:
+		return invalid("id-length", "must be a positive integer")
+	case o.Count < 0:
+		return invalid("count", "must not be negative")
+	case o.Stream && o.Count != 0:
+		return invalid("stream", "cannot be combined with a non-zero count")
+	case o.Workers < 0:
+		return invalid("workers", "must be a positive integer")
+	case o.Rate < 0:
+		return invalid("rate", "must not be negative")
+	case o.Burst < 0:
+		return invalid("burst", "must not be negative")
+	case o.FlushInterval < 0:
+		return invalid("flush-interval", "must be positive")
+	case o.UniqueMemory < 0:
+		return invalid("unique-memory", "must be a positive integer")
+	case o.Unique && o.Stream:
+		return invalid("unique", "cannot be combined with stream")
+	case o.SplitLines < 0:
+		return invalid("split-lines", "must not be negative")
+	case o.Output == "" && o.SplitLines > 0:
+		return invalid("split-lines", "requires output")
+	case o.Output == "" && o.SplitSize > 0:
+		return invalid("split-size", "requires output")
+	case o.Output == "" && o.Manifest:
+		return invalid("manifest", "requires output")
+	case o.Format != "" && !slices.Contains(formats, o.Format):
+		return invalid("format", "must be one of: "+strings.Join(formats, ", "))
+	case o.Compression != "" && !slices.Contains(compressions, o.Compression):
+		return invalid("compress", "must be one of: "+strings.Join(compressions, ", "))
+	case o.Seed != "" && !o.DRBG.IsZero():
+		return invalid("seed", "cannot be combined with DRBG options")
+	}
+
+	if o.Preset != "" {
+		expanded, err := alphabet.Expand(o.Preset)
+		if err != nil {
+			return o, &OptionError{Option: "preset", Err: err}
+		}
+		o.Alphabet = expanded
+	}
+
+	if o.Count == 0 && !o.Stream {
+		o.Count = 1
+	}
+	if o.Length == 0 {
+		o.Length = nanoid.DefaultLength
+	}
+	if o.Alphabet == "" {
+		o.Alphabet = nanoid.DefaultAlphabet
+	}
+	if o.Workers == 0 {
+		o.Workers = runtime.GOMAXPROCS(0)
+	}
+	if o.Format == "" {
+		o.Format = FormatText
+	}
+	if o.Source == "" {
+		o.Source = source.Auto
+	}
+	if o.Rate > 0 && o.Burst == 0 {
+		o.Burst = defaultBurst(o.Rate)
+	}
+	if o.FlushInterval == 0 {
+		o.FlushInterval = DefaultFlushInterval
+	}
+	if o.UniqueMemory == 0 {
+		o.UniqueMemory = DefaultUniqueMemory
+	}
+	if o.ProgressInterval <= 0 {
+		o.ProgressInterval = DefaultProgressInterval
+	}
+	return o, nil
+}
+
+// Run generates the IDs described by opts and writes them to w, or to opts.Output
+// if it is set. Invalid options are reported as an *OptionError before anything
+// is written.
+//
+// A batch ends once opts.Count IDs have been written. If ctx is done first, Run
+// stops at an ID boundary, completes the document written so far (discarding an
+// unfinished Output file), and returns context.Cause(ctx). A Stream ends without
+// error once ctx is done or w is closed. The returned Stats describe
+// whatever was written, even when an error is returned.
+func Run(ctx context.Context, opts Options, w io.Writer) (Stats, error) {
+	opts, err := opts.normalize()
+	if err != nil {
+		return Stats{}, err
+	}
+	streaming := opts.Stream
+
+	stats := Stats{Length: opts.Length}
+
+	// Never start more workers than there are IDs to generate
+	activeWorkers := opts.Workers
+	if !streaming {
+		activeWorkers = min(opts.Workers, opts.Count)
+	}
+
+	// Build the random source; it is shared by all workers since every source is safe for concurrent use
+	var reader io.Reader
+	if opts.Seed != "" {
+		if reader, err = source.NewSeeded(opts.Seed); err != nil {
+			return stats, &OptionError{Option: "seed", Err: err}
+		}
+		stats.Source = source.Seeded
+
+		// Workers would race for the shared stream, so a reproducible run uses exactly one.
+		activeWorkers = 1
+	} else if reader, stats.Source, err = source.New(opts.Source, source.WithDRBG(opts.DRBG)); err != nil {
+		return stats, &OptionError{Option: "source", Err: err}
+	}
+
+	// Configure the Nano ID generator using ConfigOptions
+	var configOpts []nanoid.Option
+	configOpts = append(configOpts, nanoid.WithLengthHint(uint16(opts.Length)))
+	configOpts = append(configOpts, nanoid.WithRandReader(reader))
+	if opts.Alphabet != nanoid.DefaultAlphabet {
+		configOpts = append(configOpts, nanoid.WithAlphabet(opts.Alphabet))
+	}
+
+	// Generator errors are about the alphabet, which may have come from Preset
+	alphabetOption := "alphabet"
+	if opts.Preset != "" {
+		alphabetOption = "preset"
+	}
+
+	// Initialize a Nano ID generator up front so that configuration errors are reported
+	// before any worker starts.
+	generator, err := nanoid.NewGenerator(configOpts...)
+	if err != nil {
+		return stats, &OptionError{Option: alphabetOption, Err: err}
+	}
+	stats.AlphabetSize = int(generator.Config().AlphabetLen())
+
+	// Build the timestamp clock for sortable IDs
+	var clock *sortable.Clock
+	if opts.Sortable {
+		enc, err := sortable.NewEncoding(opts.Alphabet)
+		if err != nil {
+			return stats, &OptionError{Option: "sortable", Err: err}
+		}
+		clock = sortable.NewClock(enc)
+		stats.TimestampWidth = clock.Width()
+	}
+
+	// Build the check character scheme over the generator's alphabet
+	var sum *checksum.Checksum
+	if opts.Checksum != "" {
+		if sum, err = checksum.New(opts.Checksum, opts.Alphabet); err != nil {
+			return stats, &OptionError{Option: "checksum", Err: err}
+		}
+		stats.Checksum, stats.ChecksumSize = sum.Scheme(), sum.Size()
+	}
+
+	// Track emitted IDs, failing fast when the batch cannot possibly be unique
+	var uniq *uniqueFilter
+	if opts.Unique {
+		keyspace := entropy.Keyspace(stats.AlphabetSize, opts.Length)
+		if float64(opts.Count) > keyspace {
+			return stats, &OptionError{Option: "count", Reason: fmt.Sprintf("%d exceeds the %.0f distinct IDs of length %d over a %d-character alphabet",
+				opts.Count, keyspace, opts.Length, stats.AlphabetSize)}
+		}
+		if limit := maxUniqueCount(keyspace); float64(opts.Count) > limit {
+			return stats, &OptionError{Option: "count", Reason: fmt.Sprintf("%d nearly exhausts the %.0f distinct IDs of length %d over a %d-character alphabet; at most %.0f can be made unique",
+				opts.Count, keyspace, opts.Length, stats.AlphabetSize, limit)}
+		}
+
+		seen, err := dedup.New(dedup.Options{MemoryLimit: opts.UniqueMemory, Expected: opts.Count, SpillDir: opts.UniqueSpillDir})
+		if err != nil {
+			return stats, err
+		}
+		defer func() { _ = seen.Close() }()
+
+		// Regenerated IDs come from the consumer goroutine; a seeded run gives them their
+		// own stream so that they do not race the worker for the shared one.
+		regenerator := generator
+		if opts.Seed != "" {
+			regenReader, err := source.NewSeeded(opts.Seed + "\x00unique")
+			if err != nil {
+				return stats, &OptionError{Option: "seed", Err: err}
+			}
+			if regenerator, err = nanoid.NewGenerator(append(slices.Clone(configOpts), nanoid.WithRandReader(regenReader))...); err != nil {
+				return stats, &OptionError{Option: alphabetOption, Err: err}
+			}
+		}
+
+		uniq = &uniqueFilter{seen: seen, generator: regenerator, length: opts.Length}
+	}
+
+	// Write to w, or to Output files that are published only once complete
+	var files *shardWriter
+	if opts.Output != "" {
+		if files, err = newShardWriter(opts.Output, opts.SplitLines > 0 || opts.SplitSize > 0, opts.Force); err != nil {
+			return stats, &OptionError{Option: "output", Err: err}
+		}
+		if opts.Manifest {
+			if err = files.checkTarget(files.manifestPath()); err != nil {
+				return stats, &OptionError{Option: "manifest", Err: err}
+			}
+		}
+		defer files.abort()
+		w = files
+	}
+
+	out, err := newFormatter(opts.Format, opts.Alphabet)
+	if err != nil {
+		return stats, &OptionError{Option: "format", Err: err}
+	}
+
+	// Use a buffered writer for efficient writing, counting the bytes that reach the output
+	// both before and after compression. The compressor runs on its own goroutine.
+	compressed := &countingWriter{w: w}
+	counter := &countingWriter{w: compressed}
+	var comp *compressor
+	openCompressor := func() error {
+		var err error
+		if comp, err = newCompressor(opts.Compression, compressionLevel(opts.Format), compressed); err != nil {
+			return err
+		}
+		counter.w = comp
+		return nil
+	}
+	if opts.Compression != "" {
+		if err = openCompressor(); err != nil {
+			return stats, &OptionError{Option: "compress", Err: err}
+		}
+		defer func() { _ = comp.Close() }()
+	}
+	writer := bufio.NewWriter(counter)
+
+	// Generate and write the specified number of Nano IDs
+	stats.Start = time.Now()
+
+	// Each worker owns its own generator; the IDs are written by this goroutine only,
+	// so whole lines are emitted and never interleaved.
+	workerOpts := workerOptions{
+		workers: activeWorkers,
+		count:   opts.Count,
+		length:  opts.Length,
+		newGenerator: func() (nanoid.Interface, error) {
+			return nanoid.NewGenerator(configOpts...)
+		},
+	}
+
+	if err = out.begin(writer); err != nil {
+		return stats, err
+	}
+
+	// flush pushes buffered output downstream, including any buffered by the formatter
+	// or held by the compressor
+	flush := func() error {
+		if f, ok := out.(flusher); ok {
+			if err := f.flush(); err != nil {
+				return err
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		if comp != nil {
+			return comp.Flush()
+		}
+		return nil
+	}
+
+	// Streams and rate-limited runs flush periodically so consumers see IDs promptly
+	var limiter *rateLimiter
+	if opts.Rate > 0 {
+		limiter = newRateLimiter(opts.Rate, opts.Burst)
+	}
+	periodicFlush := streaming || limiter != nil
+	lastFlush := time.Now()
+
+	// A split Output ends the document in the current file and publishes it once the
+	// file is full. The next file begins with the next ID, so no empty file is left behind.
+	// Sizes and lines are counted before compression.
+	var shardIDs, shardStart, shardLines int64
+	pendingBegin := false
+	shardFull := func() (bool, error) {
+		switch {
+		case opts.SplitLines > 0:
+			return shardIDs >= int64(opts.SplitLines), nil
+		case opts.SplitSize > 0:
+			if f, ok := out.(flusher); ok {
+				if err := f.flush(); err != nil {
+					return false, err
+				}
+			}
+			return uint64(counter.n-shardStart)+uint64(writer.Buffered()) >= opts.SplitSize, nil
+		default:
+			return false, nil
+		}
+	}
+	rotate := func() error {
+		if err := out.end(writer); err != nil {
+			return err
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+		if comp != nil {
+			if err := comp.Close(); err != nil {
+				return err
+			}
+		}
+		if err := files.commit(shardIDs, counter.lines-shardLines); err != nil {
+			return err
+		}
+		shardIDs, shardStart, shardLines, pendingBegin = 0, counter.n, counter.lines, true
+		return nil
+	}
+
+	index := 0
+
+	// report passes a snapshot of the run to Progress, counting output still buffered
+	lastProgress := time.Now()
+	report := func() {
+		if opts.Progress != nil {
+			opts.Progress(Progress{IDs: index, Count: opts.Count, Bytes: counter.n + int64(writer.Buffered()), Elapsed: time.Since(stats.Start)})
+		}
+	}
+
+	stats.Workers, err = generateParallel(ctx, workerOpts, func(id nanoid.ID) error {
+		if uniq != nil {
+			var err error
+			if id, err = uniq.next(id); err != nil {
+				return err
+			}
+		}
+		if clock != nil {
+			id = nanoid.ID(clock.Next() + string(id))
+		}
+		if sum != nil {
+			body, err := sum.Append(string(id))
+			if err != nil {
+				return err
+			}
+			id = nanoid.ID(body)
+		}
+		if opts.Prefix != "" {
+			id = nanoid.ID(opts.Prefix + string(id))
+		}
+
+		if limiter != nil {
+			if err := limiter.wait(ctx, flush); err != nil {
+				return err
+			}
+		}
+
+		if pendingBegin {
+			if comp != nil {
+				if err := openCompressor(); err != nil {
+					return err
+				}
+			}
+			if err := out.begin(writer); err != nil {
+				return err
+			}
+			pendingBegin = false
+		}
+		if err := out.write(writer, index, id); err != nil {
+			return err
+		}
+		index++
+
+		if files != nil {
+			shardIDs++
+			full, err := shardFull()
+			if err != nil {
+				return err
+			}
+			if full {
+				if err := rotate(); err != nil {
+					return err
+				}
+			}
+		}
+
+		if opts.Progress != nil && time.Since(lastProgress) >= opts.ProgressInterval {
+			lastProgress = time.Now()
+			report()
+		}
+
+		if periodicFlush && time.Since(lastFlush) >= opts.FlushInterval {
+			lastFlush = time.Now()
+			return flush()
+		}
+		return nil
+	})
+	stats.IDs = index
+	if uniq != nil {
+		stats.Collisions, stats.UniqueMode = uniq.collisions, uniq.seen.Mode()
+	}
+
+	// A batch cut short because ctx is done still ends on a whole ID. Its output is
+	// completed as for a full batch, so the IDs written so far form a valid document;
+	// only an unfinished Output file is discarded.
+	var stopped error
+	switch {
+	case streaming && isStreamEnd(err):
+		err = nil
+	case !streaming && index < opts.Count && ctx.Err() != nil && (err == nil || errors.Is(err, ctx.Err())):
+		stopped, err = context.Cause(ctx), nil
+	}
+	if err == nil && !pendingBegin {
+		err = out.end(writer)
+	}
+	if err != nil {
+		_ = writer.Flush()
+		stats.Bytes = counter.n
+		return stats, err
+	}
+
+	stats.Duration = time.Since(stats.Start)
+
+	if err := writer.Flush(); err != nil && !isStreamEnd(err) {
+		return stats, err
+	}
+
+	// Finish the compressed stream; a stream cut short by a closed pipe has no reader left
+	if comp != nil {
+		if err := comp.Close(); err != nil && !isStreamEnd(err) {
+			return stats, err
+		}
+		stats.CompressedBytes = compressed.n
+	}
+	stats.Bytes = counter.n
+	report()
+
+	if stopped != nil {
+		if files != nil {
+			stats.Files = slices.Clone(files.shards)
+		}
+		return stats, stopped
+	}
+
+	// Publish the last Output file and the manifest describing all of them, removing
+	// any higher-numbered files an earlier run left behind
+	if files != nil {
+		if !pendingBegin {
+			if err := files.commit(shardIDs, counter.lines-shardLines); err != nil {
+				return stats, err
+			}
+		}
+		stats.Files = slices.Clone(files.shards)
+		if err := files.removeStale(); err != nil {
+			return stats, err
+		}
+		if opts.Manifest {
+			if err := files.writeManifest(opts.Format, opts.Compression); err != nil {
+				return stats, err
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// countingWriter wraps an io.Writer and counts the bytes and lines written through it.
+type countingWriter struct {
+	w     io.Writer
+	n     int64
+	lines int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.lines += int64(bytes.Count(p[:n], []byte{'\n'}))
+	return n, err
+}
+
+// isStreamEnd reports whether err marks the normal end of an unbounded stream: the
+// stream was interrupted or timed out, or its reader went away.
+func isStreamEnd(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, syscall.EPIPE)
+}
@@ -0,0 +1,271 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package generate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sixafter/nanoid"
+	"github.com/sixafter/nanoid-cli/internal/source"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	var out bytes.Buffer
+	stats, err := Run(context.Background(), Options{Count: 10, Workers: 3}, &out)
+	is.NoError(err)
+
+	ids := strings.Split(strings.TrimSpace(out.String()), "\n")
+	is.Len(ids, 10)
+	for _, id := range ids {
+		is.Len(id, nanoid.DefaultLength)
+	}
+
+	is.Equal(10, stats.IDs)
+	is.Equal(nanoid.DefaultLength, stats.Length)
+	is.Equal(len(nanoid.DefaultAlphabet), stats.AlphabetSize)
+	is.Equal(int64(out.Len()), stats.Bytes)
+	is.Len(stats.Workers, 3)
+	is.Equal(stats.IDs, workerIDs(stats), "Expected the worker counts to add up to the total")
+	is.NotEmpty(stats.Source)
+	is.InDelta(126, stats.Entropy(), 0.1)
+}
+
+// workerIDs returns the sum of the IDs written by every worker.
+func workerIDs(stats Stats) int {
+	total := 0
+	for _, ws := range stats.Workers {
+		total += ws.IDs
+	}
+	return total
+}
+
+func TestRun_ZeroOptions(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// The zero value writes a single ID rather than an endless stream
+	var out bytes.Buffer
+	stats, err := Run(context.Background(), Options{}, &out)
+	is.NoError(err)
+	is.Equal(1, stats.IDs)
+	is.Len(strings.TrimSpace(out.String()), nanoid.DefaultLength)
+}
+
+func TestRun_Options(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	var out bytes.Buffer
+	stats, err := Run(context.Background(), Options{
+		Preset:   "hex-lower",
+		Length:   8,
+		Prefix:   "usr_",
+		Checksum: "weighted",
+		Count:    5,
+		Format:   FormatJSON,
+		Unique:   true,
+	}, &out)
+	is.NoError(err)
+	is.Equal(5, workerIDs(stats))
+	is.Equal(16, stats.AlphabetSize)
+	is.Equal(2, stats.ChecksumSize)
+	is.Equal("exact", stats.UniqueMode)
+
+	is.True(strings.HasPrefix(out.String(), "[\n"))
+	is.Equal(5, strings.Count(out.String(), `"usr_`))
+}
+
+func TestRun_OptionErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		opts Options
+		want string
+	}{
+		"length":      {Options{Length: -1}, "id-length must be a positive integer"},
+		"count":       {Options{Count: -1}, "count must not be negative"},
+		"format":      {Options{Format: "xml"}, "format must be one of: text, json, ndjson, csv, yaml"},
+		"compression": {Options{Count: 1, Compression: "zstd"}, "compress must be one of: gzip, zlib"},
+		"stream":      {Options{Count: 5, Stream: true}, "stream cannot be combined with a non-zero count"},
+		"unique":      {Options{Unique: true, Stream: true}, "unique cannot be combined with stream"},
+		"split":       {Options{Count: 1, SplitLines: 10}, "split-lines requires output"},
+		"preset":      {Options{Count: 1, Preset: "nope"}, "invalid preset"},
+		"preset set":  {Options{Count: 1, Preset: "a"}, "invalid preset"},
+		"source":      {Options{Count: 1, Source: "nope"}, "invalid source"},
+		"alphabet":    {Options{Count: 1, Alphabet: "aabc"}, "invalid alphabet"},
+		"keyspace":    {Options{Count: 5, Alphabet: "01", Length: 2, Unique: true}, "count 5 exceeds the 4 distinct IDs"},
+		"exhausted":   {Options{Count: 4094, Alphabet: "01", Length: 12, Unique: true}, "count 4094 nearly exhausts the 4096 distinct IDs of length 12 over a 2-character alphabet; at most 4093 can be made unique"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := assert.New(t)
+
+			var out bytes.Buffer
+			_, err := Run(context.Background(), tc.opts, &out)
+
+			var optErr *OptionError
+			is.True(errors.As(err, &optErr), "Expected an *OptionError, got %v", err)
+			is.ErrorContains(err, tc.want)
+			is.Zero(out.Len(), "Expected nothing to be written")
+		})
+	}
+
+	_, err := Run(context.Background(), Options{Count: 1, Source: "nope"}, io.Discard)
+	assert.ErrorIs(t, err, source.ErrUnknownSource)
+}
+
+func TestRun_Concurrent(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// Runs with different options must not share state.
+	var wg sync.WaitGroup
+	outputs := make([]bytes.Buffer, 8)
+	errs := make([]error, len(outputs))
+	for i := range outputs {
+		wg.Go(func() {
+			_, errs[i] = Run(context.Background(), Options{Length: 4 + i, Count: 100, Workers: 2}, &outputs[i])
+		})
+	}
+	wg.Wait()
+
+	for i := range outputs {
+		is.NoError(errs[i])
+		ids := strings.Split(strings.TrimSpace(outputs[i].String()), "\n")
+		is.Len(ids, 100)
+		for _, id := range ids {
+			is.Len(id, 4+i)
+		}
+	}
+}
+
+func TestRun_Seeded(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	run := func() string {
+		var out bytes.Buffer
+		stats, err := Run(context.Background(), Options{Seed: "fixtures", Count: 20, Workers: 4}, &out)
+		is.NoError(err)
+		is.Len(stats.Workers, 1, "Seeded runs use a single worker")
+		is.Equal(source.Seeded, stats.Source)
+		return out.String()
+	}
+	is.Equal(run(), run())
+}
+
+func TestRun_Cancel(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// A stream ends without error once its context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var out bytes.Buffer
+	stats, err := Run(ctx, Options{Stream: true, Workers: 2}, &out)
+	is.NoError(err)
+	is.Positive(stats.IDs)
+	is.Equal(stats.IDs, strings.Count(out.String(), "\n"), "Expected only whole lines")
+	is.Equal(stats.IDs, workerIDs(stats), "Expected IDs discarded when the stream stopped not to be counted")
+
+	// A batch cut short reports why.
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = Run(ctx, Options{Count: 1000}, io.Discard)
+	is.ErrorIs(err, context.Canceled)
+}
+
+func TestRun_Progress(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// Progress is called from the goroutine that calls Run, so no locking is needed.
+	var snapshots []Progress
+	var out bytes.Buffer
+	_, err := Run(context.Background(), Options{
+		Count:            50000,
+		Workers:          2,
+		Progress:         func(p Progress) { snapshots = append(snapshots, p) },
+		ProgressInterval: time.Nanosecond,
+	}, &out)
+	is.NoError(err)
+
+	is.Greater(len(snapshots), 1, "Expected progress during the run as well as at the end")
+	for i := 1; i < len(snapshots); i++ {
+		is.GreaterOrEqual(snapshots[i].IDs, snapshots[i-1].IDs, "Expected progress never to go backwards")
+	}
+
+	last := snapshots[len(snapshots)-1]
+	is.Equal(50000, last.IDs)
+	is.Equal(50000, last.Count)
+	is.Equal(int64(out.Len()), last.Bytes)
+	is.Zero(last.Remaining())
+}
+
+func TestRun_UniqueBoundary(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	tests := []struct {
+		length, count int
+	}{
+		{2, 4},     // a small keyspace can be filled completely
+		{10, 1024}, // as can one whose last ID needs maxExpectedAttempts on average
+		{12, 4093}, // the largest count accepted for 4096 distinct IDs
+	}
+
+	for _, tc := range tests {
+		var out bytes.Buffer
+		stats, err := Run(context.Background(), Options{Alphabet: "01", Length: tc.length, Count: tc.count, Workers: 2, Unique: true}, &out)
+		is.NoError(err, "%d of length %d", tc.count, tc.length)
+		is.Equal(tc.count, stats.IDs)
+
+		seen := make(map[string]bool)
+		for _, id := range strings.Fields(out.String()) {
+			seen[id] = true
+		}
+		is.Len(seen, tc.count, "Expected %d distinct IDs", tc.count)
+	}
+
+	is.Equal(float64(4), maxUniqueCount(4))
+	is.Equal(float64(1024), maxUniqueCount(1024))
+	is.Equal(float64(4093), maxUniqueCount(4096))
+	is.Equal(float64(1<<30-(1<<20)+1), maxUniqueCount(1<<30))
+}
+
+func TestRun_WorkerIDs(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// Duplicates regenerated under Unique are not counted twice
+	stats, err := Run(context.Background(), Options{Alphabet: "01", Length: 10, Count: 900, Workers: 3, Unique: true}, io.Discard)
+	is.NoError(err)
+	is.Positive(stats.Collisions)
+	is.Equal(900, workerIDs(stats))
+
+	// Neither are the IDs of batches discarded when a run is cut short
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	stats, err = Run(ctx, Options{Count: 100_000_000, Workers: 4}, io.Discard)
+	is.ErrorIs(err, context.DeadlineExceeded)
+	is.Less(stats.IDs, 100_000_000)
+	is.Equal(stats.IDs, workerIDs(stats))
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package generate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// outputPerm is the permission of published output files.
+const outputPerm = 0o644
+
+// ErrOutputExists is returned when an output file already exists and Options.Force is not set.
+var ErrOutputExists = errors.New("output file already exists")
+
+// ManifestFile describes one published output file in the manifest.
+type ManifestFile struct {
+	// Path is the file name, relative to the manifest.
+	Path string `json:"path"`
+
+	// IDs is the number of IDs in the file.
+	IDs int64 `json:"ids"`
+
+	// Lines is the number of newline-terminated lines in the file, before any compression.
+	Lines int64 `json:"lines"`
+
+	// Bytes is the size of the file as written, after any compression.
+	Bytes int64 `json:"bytes"`
+
+	// SHA256 is the hex-encoded SHA-256 digest of the file.
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest is the sidecar written when Options.Manifest is set, listing every output file.
+type Manifest struct {
+	Format      string         `json:"format"`
+	Compression string         `json:"compression,omitempty"`
+	IDs         int64          `json:"ids"`
+	Files       []ManifestFile `json:"files"`
+}
+
+// shardWriter writes output to the file named by --output or, when splitting, to
+// a sequence of numbered files. Every file is written to a temporary file in the
+// same directory and renamed into place only once complete, so readers never
+// observe a partial file.
+//
+// Write opens the next file on demand; commit publishes the current one.
+type shardWriter struct {
+	path  string
+	split bool
+	force bool
+
+	file  *os.File
+	hash  hash.Hash
+	bytes int64
+
+	shards []ManifestFile
+}
+
+// newShardWriter returns a writer for path, failing early if its first file
+// already exists and force is false.
+func newShardWriter(path string, split, force bool) (*shardWriter, error) {
+	w := &shardWriter{path: path, split: split, force: force}
+	if err := w.checkTarget(w.target()); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// compressedExts are the file extensions that follow the format's own extension,
+// as in ids.txt.gz.
+var compressedExts = []string{".gz", ".gzip", ".zz", ".zlib"}
+
+// splitExt splits path into its stem and extension, keeping a compression
+// extension together with the one before it: ids.txt.gz yields ids and .txt.gz.
+func splitExt(path string) (string, string) {
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(path, ext)
+	if slices.Contains(compressedExts, ext) {
+		inner := filepath.Ext(stem)
+		stem, ext = strings.TrimSuffix(stem, inner), inner+ext
+	}
+	return stem, ext
+}
+
+// target returns the path of the current file: path itself, or its numbered
+// shard, such as ids-00002.txt for ids.txt.
+func (w *shardWriter) target() string {
+	if !w.split {
+		return w.path
+	}
+	stem, ext := splitExt(w.path)
+	return fmt.Sprintf("%s-%05d%s", stem, len(w.shards)+1, ext)
+}
+
+// removeStale removes the numbered shards, left by an earlier run, beyond those
+// published by this one, so that a run with force leaves exactly the files its
+// manifest lists. Without force, or without splitting, there is nothing to remove.
+func (w *shardWriter) removeStale() error {
+	if !w.split || !w.force {
+		return nil
+	}
+
+	stem, ext := splitExt(w.path)
+	prefix := filepath.Base(stem) + "-"
+	entries, err := os.ReadDir(filepath.Dir(w.path))
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if !e.Type().IsRegular() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		digits := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		n, err := strconv.Atoi(digits)
+		if err != nil || digits != fmt.Sprintf("%05d", n) || n <= len(w.shards) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(filepath.Dir(w.path), name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// manifestPath returns the path of the sidecar manifest, such as ids.manifest.json for ids.txt.
+func (w *shardWriter) manifestPath() string {
+	stem, _ := splitExt(w.path)
+	return stem + ".manifest.json"
+}
+
+// checkTarget protects an existing file at path unless force is set.
+func (w *shardWriter) checkTarget(path string) error {
+	if w.force {
+		return nil
+	}
+	if _, err := os.Lstat(path); err == nil {
+		return fmt.Errorf("%w: %s (use --force to overwrite)", ErrOutputExists, path)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Write writes p to the current file, opening it if necessary.
+func (w *shardWriter) Write(p []byte) (int, error) {
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.hash.Write(p[:n])
+	w.bytes += int64(n)
+	return n, err
+}
+
+// open creates the temporary file for the current target.
+func (w *shardWriter) open() error {
+	target := w.target()
+	if err := w.checkTarget(target); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	w.file, w.hash, w.bytes = file, sha256.New(), 0
+	return nil
+}
+
+// commit publishes the current file, which holds ids IDs in the given number of
+// lines, creating it empty if nothing was written. The next Write starts a new file.
+func (w *shardWriter) commit(ids, lines int64) error {
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+
+	// The target is checked again, in case it was created while the file was written.
+	target := w.target()
+	if err := w.checkTarget(target); err != nil {
+		w.abort()
+		return err
+	}
+	if err := publish(w.file, target); err != nil {
+		w.abort()
+		return err
+	}
+
+	w.shards = append(w.shards, ManifestFile{
+		Path:   filepath.Base(target),
+		IDs:    ids,
+		Lines:  lines,
+		Bytes:  w.bytes,
+		SHA256: hex.EncodeToString(w.hash.Sum(nil)),
+	})
+	w.file = nil
+	return nil
+}
+
+// abort discards the current file, if any. Published files are kept.
+func (w *shardWriter) abort() {
+	if w.file == nil {
+		return
+	}
+	_ = w.file.Close()
+	_ = os.Remove(w.file.Name())
+	w.file = nil
+}
+
+// writeManifest atomically writes the sidecar manifest describing every published file.
+func (w *shardWriter) writeManifest(format, compression string) error {
+	m := Manifest{Format: format, Compression: compression, Files: w.shards}
+	for _, s := range w.shards {
+		m.IDs += s.IDs
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := w.manifestPath()
+	if err := w.checkTarget(path); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return err
+	}
+	if err := publish(file, path); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	return nil
+}
+
+// publish syncs and closes the temporary file and renames it to path.
+func publish(file *os.File, path string) error {
+	if err := file.Chmod(outputPerm); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
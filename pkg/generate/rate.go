@@ -0,0 +1,91 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package generate
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseRate parses a rate such as "1000", "1000/s", "60/m", or "10/h" and returns it in events per second.
+func ParseRate(s string) (float64, error) {
+	value, unit, _ := strings.Cut(strings.TrimSpace(s), "/")
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n <= 0 || math.IsInf(n, 0) || math.IsNaN(n) {
+		return 0, fmt.Errorf("invalid rate %q: must be a positive number optionally followed by /s, /m, or /h", s)
+	}
+
+	switch unit {
+	case "", "s":
+		return n, nil
+	case "m":
+		return n / 60, nil
+	case "h":
+		return n / 3600, nil
+	default:
+		return 0, fmt.Errorf("invalid rate %q: unit must be /s, /m, or /h", s)
+	}
+}
+
+// defaultBurst returns the burst size used when none is given: a tenth of a second's
+// worth of events, and at least one.
+func defaultBurst(rate float64) int {
+	return max(1, int(math.Ceil(rate/10)))
+}
+
+// rateLimiter is a token bucket that limits events to rate per second while allowing
+// bursts of up to burst events. It is not safe for concurrent use.
+type rateLimiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a rateLimiter whose bucket starts full.
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until an event is permitted or ctx is done. beforeSleep, if not nil,
+// is called before blocking so callers can flush buffered output while idle.
+func (l *rateLimiter) wait(ctx context.Context, beforeSleep func() error) error {
+	for {
+		now := time.Now()
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		l.last = now
+
+		if l.tokens >= 1 {
+			l.tokens--
+			return nil
+		}
+
+		if beforeSleep != nil {
+			if err := beforeSleep(); err != nil {
+				return err
+			}
+		}
+
+		delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package generate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRate(t *testing.T) {
+	is := assert.New(t)
+
+	tests := map[string]float64{
+		"1000":   1000,
+		"1000/s": 1000,
+		"60/m":   1,
+		"7200/h": 2,
+		"0.5/s":  0.5,
+	}
+	for in, want := range tests {
+		got, err := ParseRate(in)
+		is.NoError(err, in)
+		is.InDelta(want, got, 1e-9, in)
+	}
+
+	for _, in := range []string{"", "0", "-5/s", "abc", "10/d", "NaN", "Inf/s"} {
+		_, err := ParseRate(in)
+		is.Error(err, in)
+	}
+}
+
+func TestDefaultBurst(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal(1, defaultBurst(0.1))
+	is.Equal(1, defaultBurst(10))
+	is.Equal(100, defaultBurst(1000))
+}
+
+func TestRateLimiter(t *testing.T) {
+	is := assert.New(t)
+
+	limiter := newRateLimiter(50, 5)
+
+	// The bucket starts full, so the burst passes without waiting.
+	start := time.Now()
+	for range 5 {
+		is.NoError(limiter.wait(context.Background(), nil))
+	}
+	is.Less(time.Since(start), 20*time.Millisecond)
+
+	// The next events are paced at the configured rate, flushing before each wait.
+	flushes := 0
+	start = time.Now()
+	for range 5 {
+		is.NoError(limiter.wait(context.Background(), func() error {
+			flushes++
+			return nil
+		}))
+	}
+	is.GreaterOrEqual(time.Since(start), 80*time.Millisecond)
+	is.Positive(flushes)
+}
+
+func TestRateLimiter_Cancelled(t *testing.T) {
+	is := assert.New(t)
+
+	limiter := newRateLimiter(1.0/3600, 1)
+	is.NoError(limiter.wait(context.Background(), nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	is.ErrorIs(limiter.wait(ctx, nil), context.Canceled)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package generate
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sixafter/nanoid"
+	"github.com/sixafter/nanoid-cli/internal/dedup"
+)
+
+// maxUniqueAttempts bounds the consecutive regenerations tried for a single ID, so a
+// nearly exhausted keyspace fails instead of spinning forever.
+const maxUniqueAttempts = 1 << 20
+
+// maxExpectedAttempts bounds the expected number of attempts needed to find the last
+// ID of a unique batch. Batches that would need more are rejected before any work is
+// done, and the chance of then exhausting maxUniqueAttempts is negligible.
+const maxExpectedAttempts = 1024
+
+// maxUniqueCount returns the largest number of IDs that can be made unique within a
+// keyspace of the given size. The last of count IDs is found, on average, after
+// keyspace/(keyspace-count+1) attempts, so small keyspaces can be filled completely
+// while large ones must leave a fraction of 1/maxExpectedAttempts unused.
+func maxUniqueCount(keyspace float64) float64 {
+	return math.Min(keyspace, math.Floor(keyspace+1-keyspace/maxExpectedAttempts))
+}
+
+// uniqueFilter replaces IDs that were already emitted with freshly generated ones.
+// It runs on the goroutine that owns the output writer, so it needs no locking.
+type uniqueFilter struct {
+	seen      *dedup.Set
+	generator nanoid.Interface
+	length    int
+
+	// collisions is the number of IDs that had to be regenerated.
+	collisions int
+}
+
+// next returns id if it has not been emitted before, and otherwise a regenerated
+// ID that has not.
+func (u *uniqueFilter) next(id nanoid.ID) (nanoid.ID, error) {
+	for range maxUniqueAttempts {
+		added, err := u.seen.Add(string(id))
+		if err != nil {
+			return nanoid.EmptyID, err
+		}
+		if added {
+			return id, nil
+		}
+
+		u.collisions++
+		if id, err = u.generator.NewWithLength(u.length); err != nil {
+			return nanoid.EmptyID, err
+		}
+	}
+
+	return nanoid.EmptyID, fmt.Errorf("no unused ID found after %d attempts; the keyspace is nearly exhausted", maxUniqueAttempts)
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2024-2025 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package generate
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sixafter/nanoid"
+)
+
+// batchSize is the number of IDs a worker accumulates before handing them to the writer.
+// Batching amortizes channel overhead and guarantees that lines are never interleaved,
+// because only the single consuming goroutine ever touches the output writer.
+const batchSize = 1024
+
+// unbounded is the per-worker share that makes a worker generate until cancelled.
+const unbounded = -1
+
+// WorkerStats records the work performed by a single generation worker.
+type WorkerStats struct {
+	// IDs is the number of the worker's IDs that were written. IDs generated but
+	// discarded, because the run stopped before writing them, are not counted, so
+	// the IDs of all workers add up to Stats.IDs.
+	IDs int
+
+	// Duration is the time the worker spent generating IDs, excluding time spent
+	// waiting for the writer to accept a batch.
+	Duration time.Duration
+}
+
+// Throughput returns the number of the worker's IDs written per second it spent generating.
+func (s WorkerStats) Throughput() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+	return float64(s.IDs) / s.Duration.Seconds()
+}
+
+// workerBatch is a batch of IDs and the index of the worker that generated them.
+type workerBatch struct {
+	worker int
+	ids    []nanoid.ID
+}
+
+// workerOptions configures a parallel generation run.
+type workerOptions struct {
+	// workers is the number of goroutines generating IDs.
+	workers int
+
+	// count is the total number of IDs to generate across all workers.
+	// Zero generates IDs until the context is cancelled.
+	count int
+
+	// length is the length of each generated ID.
+	length int
+
+	// newGenerator constructs the generator owned by a single worker.
+	newGenerator func() (nanoid.Interface, error)
+}
+
+// generateParallel splits opts.count across opts.workers goroutines, each owning its
+// own generator, and passes every generated ID to emit on the calling goroutine.
+// When opts.count is zero, workers generate until ctx is cancelled.
+//
+// emit is never called concurrently, so it may write to a non-thread-safe writer.
+// The first error returned by a worker or by emit stops the run and is returned.
+func generateParallel(ctx context.Context, opts workerOptions, emit func(nanoid.ID) error) ([]WorkerStats, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	batches := make(chan workerBatch, opts.workers*2)
+	stats := make([]WorkerStats, opts.workers)
+
+	share, remainder := opts.count/opts.workers, opts.count%opts.workers
+	for i := range opts.workers {
+		n := share
+		if i < remainder {
+			n++
+		}
+		if opts.count == 0 {
+			n = unbounded
+		}
+
+		wg.Go(func() {
+			if err := runWorker(ctx, opts, i, n, batches, &stats[i]); err != nil {
+				fail(err)
+			}
+		})
+	}
+
+	go func() {
+		wg.Wait()
+		close(batches)
+	}()
+
+	for batch := range batches {
+		if ctx.Err() != nil {
+			// Drain remaining batches so that blocked workers can observe cancellation.
+			continue
+		}
+
+		// Count IDs as they are written, so that discarded IDs are not credited to a worker
+		for _, id := range batch.ids {
+			if ctx.Err() != nil {
+				break
+			}
+			if err := emit(id); err != nil {
+				fail(err)
+				break
+			}
+			stats[batch.worker].IDs++
+		}
+	}
+
+	return stats, firstErr
+}
+
+// runWorker generates n IDs (or, if n is unbounded, IDs until ctx is cancelled) in
+// batches and sends them to out, recording the time it spends generating in stats.
+func runWorker(ctx context.Context, opts workerOptions, worker, n int, out chan<- workerBatch, stats *WorkerStats) error {
+	generator, err := opts.newGenerator()
+	if err != nil {
+		return err
+	}
+
+	for n != 0 && ctx.Err() == nil {
+		size := batchSize
+		if n != unbounded {
+			size = min(n, batchSize)
+		}
+		batch := make([]nanoid.ID, size)
+
+		start := time.Now()
+		for i := range batch {
+			batch[i], err = generator.NewWithLength(opts.length)
+			if err != nil {
+				return err
+			}
+		}
+		stats.Duration += time.Since(start)
+		if n != unbounded {
+			n -= size
+		}
+
+		select {
+		case out <- workerBatch{worker: worker, ids: batch}:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	return nil
+}